@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/felipevolpatto/genesis/internal/answers"
+	"github.com/felipevolpatto/genesis/internal/config"
+	"github.com/felipevolpatto/genesis/internal/diff"
+	"github.com/felipevolpatto/genesis/internal/glob"
+	"github.com/spf13/cobra"
+)
+
+var diffStat bool
+
+// diffContext is the number of unchanged lines shown around each change
+const diffContext = 3
+
+// fileDiff is the difference of a generated file between the template and
+// the project
+type fileDiff struct {
+	path     string
+	template []byte
+	project  []byte
+	deleted  bool
+}
+
+func init() {
+	diffCmd := &cobra.Command{
+		Use:   "diff [path...]",
+		Short: "Show how the project differs from its template",
+		Long: `Show how the project differs from its template.
+
+The template recorded in genesis.toml is rendered at the recorded commit (or
+version, for projects that do not record one) with the recorded answers, and
+the generated files are compared with the files in the project. Secret
+variables, which are not recorded, take their default values. Files the
+project added are not shown.
+
+Paths are relative to the project root and may be glob patterns, where "**"
+matches any number of directories. A directory matches every file below it.`,
+		RunE: runDiff,
+	}
+
+	diffCmd.Flags().BoolVar(&diffStat, "stat", false, "Show the number of changed lines per file instead of the diff")
+
+	rootCmd.AddCommand(diffCmd)
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	for _, pattern := range args {
+		if err := glob.Validate(filepath.ToSlash(pattern)); err != nil {
+			return err
+		}
+	}
+
+	// Find and parse genesis.toml
+	configPath, err := config.FindProjectConfig()
+	if err != nil {
+		return fmt.Errorf("failed to find genesis.toml: %w", err)
+	}
+	projectDir := filepath.Dir(configPath)
+
+	projectConfig, err := config.ParseProjectConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to parse genesis.toml: %w", err)
+	}
+	project := projectConfig.Project
+	if project.TemplateURL == "" {
+		return fmt.Errorf("genesis.toml does not record a template_url")
+	}
+	revision, err := recordedRevision(project)
+	if err != nil {
+		return err
+	}
+
+	// Render the pristine project
+	rendered, err := renderTemplate(project.TemplateURL, revision, project.Answers, answers.Defaults)
+	if err != nil {
+		return fmt.Errorf("failed to render template: %w", err)
+	}
+	defer os.RemoveAll(rendered.dir)
+
+	diffs, err := diffProject(rendered.dir, projectDir, args)
+	if err != nil {
+		return err
+	}
+
+	if diffStat {
+		printDiffStat(cmd.OutOrStdout(), diffs)
+		return nil
+	}
+	for _, d := range diffs {
+		printFileDiff(cmd.OutOrStdout(), d)
+	}
+	return nil
+}
+
+// diffProject compares the files rendered in templateDir that match one
+// of the patterns, or all of them if there are none, with the files in
+// projectDir and returns those that differ
+func diffProject(templateDir, projectDir string, patterns []string) ([]fileDiff, error) {
+	var diffs []fileDiff
+	err := filepath.WalkDir(templateDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(templateDir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == "genesis.toml" || !matchesAny(patterns, rel) {
+			return nil
+		}
+
+		template, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		project, err := os.ReadFile(filepath.Join(projectDir, filepath.FromSlash(rel)))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		deleted := os.IsNotExist(err)
+
+		if deleted || !bytes.Equal(template, project) {
+			diffs = append(diffs, fileDiff{path: rel, template: template, project: project, deleted: deleted})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare project with template: %w", err)
+	}
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].path < diffs[j].path })
+	return diffs, nil
+}
+
+// matchesAny reports whether a slash-separated path matches one of the
+// patterns, or lies below a directory matching one. No patterns match
+// every path.
+func matchesAny(patterns []string, path string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		pattern = strings.TrimSuffix(filepath.ToSlash(filepath.Clean(pattern)), "/")
+		if pattern == "." || glob.Match(pattern, path) || glob.Match(pattern+"/**", path) {
+			return true
+		}
+	}
+	return false
+}
+
+// printFileDiff prints the difference of a file in unified diff format
+func printFileDiff(w io.Writer, d fileDiff) {
+	from, to := "a/"+d.path, "b/"+d.path
+	if d.deleted {
+		to = "/dev/null"
+	}
+
+	if diff.IsBinary(d.template) || diff.IsBinary(d.project) {
+		fmt.Fprintf(w, "Binary files %s and %s differ\n", from, to)
+		return
+	}
+	fmt.Fprint(w, diff.Unified(from, to, string(d.template), string(d.project), diffContext))
+}
+
+// printDiffStat prints the number of changed lines per file, followed by
+// a summary
+func printDiffStat(w io.Writer, diffs []fileDiff) {
+	if len(diffs) == 0 {
+		return
+	}
+
+	width := 0
+	for _, d := range diffs {
+		width = max(width, len(d.path))
+	}
+
+	totalInsertions, totalDeletions := 0, 0
+	for _, d := range diffs {
+		if diff.IsBinary(d.template) || diff.IsBinary(d.project) {
+			fmt.Fprintf(w, " %-*s | Bin\n", width, d.path)
+			continue
+		}
+		insertions, deletions := diff.Stat(string(d.template), string(d.project))
+		totalInsertions += insertions
+		totalDeletions += deletions
+		fmt.Fprintf(w, " %-*s | %d %s\n", width, d.path, insertions+deletions, statBar(insertions, deletions))
+	}
+
+	fmt.Fprintf(w, " %d file(s) changed, %d insertion(s)(+), %d deletion(s)(-)\n", len(diffs), totalInsertions, totalDeletions)
+}
+
+// statBar draws the insertions and deletions of a file as a bar of at most
+// 40 characters
+func statBar(insertions, deletions int) string {
+	const maxWidth = 40
+	if total := insertions + deletions; total > maxWidth {
+		insertions = insertions * maxWidth / total
+		deletions = maxWidth - insertions
+	}
+	return strings.Repeat("+", insertions) + strings.Repeat("-", deletions)
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/felipevolpatto/genesis/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffCommand(t *testing.T) {
+	templateDir := setupTemplateRepo(t, map[string]string{
+		"template.toml": `version = "1.0"
+
+[vars]
+  name = { prompt = "Name:", default = "demo" }`,
+		"README.md.tmpl":     "# {{ .name }}\n\nIntro\n",
+		"src/main.go.tmpl":   "package main\n",
+		"src/helper.go":      "package main\n\nfunc helper() {}\n",
+		"docs/unchanged.txt": "same\n",
+	})
+	workDir := t.TempDir()
+	_, err := executeInDir(t, workDir, "new", "shop", "--template", templateDir, "--version", "master", "--yes", "--data", "name=shop")
+	require.NoError(t, err)
+	projectDir := filepath.Join(workDir, "shop")
+
+	// Customize the generated project
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "README.md"), []byte("# shop\n\nOur intro\nMore\n"), 0644))
+	require.NoError(t, os.Remove(filepath.Join(projectDir, "src", "helper.go")))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "local.txt"), []byte("local\n"), 0644))
+
+	output, err := executeInDir(t, projectDir, "diff")
+	require.NoError(t, err)
+	assert.Equal(t, `--- a/README.md
++++ b/README.md
+@@ -1,3 +1,4 @@
+ # shop
+ 
+-Intro
++Our intro
++More
+--- a/src/helper.go
++++ /dev/null
+@@ -1,3 +0,0 @@
+-package main
+-
+-func helper() {}
+`, output)
+
+	// Paths filter the files, and directories match the files below them
+	output, err = executeInDir(t, projectDir, "diff", "src")
+	require.NoError(t, err)
+	assert.NotContains(t, output, "README.md")
+	assert.Contains(t, output, "--- a/src/helper.go")
+
+	output, err = executeInDir(t, projectDir, "diff", "*.md", "docs")
+	require.NoError(t, err)
+	assert.Contains(t, output, "--- a/README.md")
+	assert.NotContains(t, output, "helper.go")
+
+	output, err = executeInDir(t, projectDir, "diff", "--stat")
+	require.NoError(t, err)
+	assert.Equal(t, ` README.md     | 3 ++-
+ src/helper.go | 3 ---
+ 2 file(s) changed, 2 insertion(s)(+), 4 deletion(s)(-)
+`, output)
+
+	_, err = executeInDir(t, projectDir, "diff", "[")
+	assert.ErrorContains(t, err, "invalid pattern")
+}
+
+func TestDiffRelativeTemplatePath(t *testing.T) {
+	templateDir := setupTemplateRepo(t, map[string]string{
+		"template.toml": `version = "1.0"`,
+		"README.md":     "v1\n",
+	})
+	workDir := t.TempDir()
+	relDir, err := filepath.Rel(workDir, templateDir)
+	require.NoError(t, err)
+
+	// A template given by a relative path is recorded as an absolute one,
+	// so diff also finds it from within the project
+	_, err = executeInDir(t, workDir, "new", "shop", "--template", relDir, "--version", "master", "--yes")
+	require.NoError(t, err)
+	projectDir := filepath.Join(workDir, "shop")
+
+	projectConfig, err := config.ParseProjectConfig(filepath.Join(projectDir, "genesis.toml"))
+	require.NoError(t, err)
+	assert.Equal(t, templateDir, projectConfig.Project.TemplateURL)
+
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "README.md"), []byte("v1 local\n"), 0644))
+	output, err := executeInDir(t, projectDir, "diff", "--stat")
+	require.NoError(t, err)
+	assert.Contains(t, output, "README.md")
+}
+
+func TestRecordedTemplateCommit(t *testing.T) {
+	templateDir := setupTemplateRepo(t, map[string]string{
+		"template.toml": `version = "1.0"`,
+		"README.md":     "v1\n",
+	})
+	workDir := t.TempDir()
+	_, err := executeInDir(t, workDir, "new", "shop", "--template", templateDir, "--version", "master", "--yes")
+	require.NoError(t, err)
+	projectDir := filepath.Join(workDir, "shop")
+
+	projectConfig, err := config.ParseProjectConfig(filepath.Join(projectDir, "genesis.toml"))
+	require.NoError(t, err)
+	assert.Equal(t, "master", projectConfig.Project.TemplateVersion)
+	assert.Len(t, projectConfig.Project.TemplateCommit, 40)
+
+	// Moving the branch does not change what the project is compared with
+	// or replayed from
+	commitFiles(t, templateDir, map[string]string{"README.md": "v2\n"}, nil, "")
+
+	output, err := executeInDir(t, projectDir, "diff")
+	require.NoError(t, err)
+	assert.Empty(t, output)
+
+	_, err = executeInDir(t, workDir, "new", "replayed", "--replay", "shop", "--yes")
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(workDir, "replayed", "README.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "v1\n", string(content))
+
+	replayed, err := config.ParseProjectConfig(filepath.Join(workDir, "replayed", "genesis.toml"))
+	require.NoError(t, err)
+	assert.Equal(t, projectConfig.Project, replayed.Project)
+}
@@ -2,8 +2,12 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/felipevolpatto/genesis/internal/answers"
 	"github.com/felipevolpatto/genesis/internal/config"
 	"github.com/felipevolpatto/genesis/internal/runner"
 	"github.com/felipevolpatto/genesis/internal/scaffolder"
@@ -15,8 +19,15 @@ var (
 	templateURL string
 	skipPrompts bool
 	version     string
+	dataPairs   []string
+	dataFile    string
+	replayDir   string
 )
 
+// varEnvPrefix prefixes the environment variables that set template
+// variables, e.g. GENESIS_VAR_NAME for the variable name
+const varEnvPrefix = "GENESIS_VAR_"
+
 func init() {
 	newCmd := &cobra.Command{
 		Use:   "new [project-name]",
@@ -24,33 +35,70 @@ func init() {
 		Long: `Create a new project from a template.
 
 The template should be a Git repository containing a template.toml file and the project structure.
-Template files ending in .tmpl will be processed using Go's text/template package.`,
+Template files ending in .tmpl will be processed using Go's text/template package.
+
+The template may also be a local directory, given as a path or a file:// URL.
+Without --version it is used as it is, including uncommitted changes, and it
+does not need to be a Git repository; no commit is recorded then, so the
+project cannot be updated or compared with the template later.
+
+Variables can be set without prompting from a file (--data-file), from
+GENESIS_VAR_<NAME> environment variables and with --data key=value, in
+increasing order of precedence. Only the remaining variables are prompted
+for, or take their defaults with --yes.
+
+With --replay, the template, version and answers recorded in the
+genesis.toml of an existing project are used to generate it again. Flags
+given explicitly take precedence over the recorded values.`,
 		Args: cobra.ExactArgs(1),
 		RunE: runNew,
 	}
 
-	newCmd.Flags().StringVarP(&templateURL, "template", "t", "", "Template URL or path (required)")
+	newCmd.Flags().StringVarP(&templateURL, "template", "t", "", "Template URL or path (required unless --replay is given)")
 	newCmd.Flags().BoolVarP(&skipPrompts, "yes", "y", false, "Skip all prompts and use default values")
 	newCmd.Flags().StringVarP(&version, "version", "v", "", "Template version (tag, branch, or commit hash)")
+	newCmd.Flags().StringArrayVar(&dataPairs, "data", nil, "Set a template variable as key=value (can be repeated)")
+	newCmd.Flags().StringVar(&dataFile, "data-file", "", "Read template variables from a TOML, YAML or JSON file")
+	newCmd.Flags().StringVar(&replayDir, "replay", "", "Generate a project again from the template and answers recorded in its genesis.toml")
 
-	if err := newCmd.MarkFlagRequired("template"); err != nil {
-		panic(fmt.Sprintf("failed to mark template flag as required: %v", err))
-	}
 	rootCmd.AddCommand(newCmd)
 }
 
 func runNew(cmd *cobra.Command, args []string) error {
 	projectName := args[0]
+	url, ref := templateURL, version
+	revision := ref
+
+	// Take the template and answers of the project to replay
+	var recorded map[string]interface{}
+	if replayDir != "" {
+		projectConfig, err := config.ParseProjectConfig(filepath.Join(replayDir, "genesis.toml"))
+		if err != nil {
+			return fmt.Errorf("failed to read project to replay: %w", err)
+		}
+		if url == "" {
+			url = projectConfig.Project.TemplateURL
+		}
+		if ref == "" {
+			ref = projectConfig.Project.TemplateVersion
+			revision = projectConfig.Project.Revision()
+		}
+		recorded = projectConfig.Project.Answers
+	}
 
 	// Validate template URL
-	if templateURL == "" {
+	if url == "" {
 		return fmt.Errorf("template URL is required")
 	}
+	url, err := scaffolder.ResolveSource(url)
+	if err != nil {
+		return err
+	}
 
-	// Clone template repository
-	templateDir, err := scaffolder.CloneTemplate(templateURL, version)
+	// Clone the template repository, or copy a local template directory
+	templateDir, commit, err := scaffolder.FetchTemplate(url, revision)
 	if err != nil {
-		return fmt.Errorf("failed to clone repository: %w", err)
+		return fmt.Errorf("failed to fetch template: %w", err)
 	}
 	defer func() {
 		if err := scaffolder.CleanupTemplate(templateDir); err != nil {
@@ -64,28 +112,60 @@ func runNew(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to parse template config: %w", err)
 	}
 
-	// Get variable values
-	variables := make(map[string]string)
-	if !skipPrompts {
-		variables, err = tui.PromptForVariables(templateConfig.Vars)
-		if err != nil {
-			return fmt.Errorf("failed to get variable values: %w", err)
-		}
-	} else {
-		// Use default values
-		for name, v := range templateConfig.Vars {
-			variables[name] = v.Default
-		}
+	// Get variable values, asking only for those that were not given
+	given, sources, err := givenAnswers(templateConfig)
+	if err != nil {
+		return err
+	}
+	values := knownAnswers(templateConfig, recorded)
+	for name, value := range given {
+		values[name] = value
+	}
+	ask := tui.Prompt
+	if skipPrompts {
+		ask = answers.Defaults
+	}
+	ask, err = answers.Given(templateConfig.Vars, values, ask)
+	if err != nil {
+		return fmt.Errorf("invalid answers: %w", err)
+	}
+	skip := func(name string, _ config.Variable) error {
+		return unusedAnswer(cmd.ErrOrStderr(), name, sources[name])
+	}
+	variables, err := answers.ResolveSkipping(templateConfig.Vars, templateConfig.VarNames(), ask, skip)
+	if err != nil {
+		return fmt.Errorf("failed to get variable values: %w", err)
 	}
 
 	// Create project directory
 	projectDir := filepath.Join(".", projectName)
 	s := scaffolder.New(templateDir, projectDir, variables, templateConfig)
 
+	// Expose the variables and the directories involved to hooks
+	absProjectDir, err := filepath.Abs(projectDir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve project directory: %w", err)
+	}
+	hookEnv := runner.HookEnv(variables, projectName, templateDir, absProjectDir)
+
+	// Render hook commands with the template variables
+	preHooks, err := s.RenderHooks(templateConfig.Hooks.Pre)
+	if err != nil {
+		return fmt.Errorf("failed to render pre-hooks: %w", err)
+	}
+	postHooks, err := s.RenderHooks(templateConfig.Hooks.Post)
+	if err != nil {
+		return fmt.Errorf("failed to render post-hooks: %w", err)
+	}
+
 	// Run pre-hooks
-	if len(templateConfig.Hooks.Pre) > 0 {
+	if len(preHooks) > 0 {
 		fmt.Println("Running pre-hooks...")
-		if err := runner.RunHooks(templateConfig.Hooks.Pre, projectDir); err != nil {
+		// Pre-hooks run in the project directory, so it has to exist
+		if err := os.MkdirAll(projectDir, 0755); err != nil {
+			return fmt.Errorf("failed to create project directory: %w", err)
+		}
+		if err := runner.RunHooks(preHooks, projectDir, hookEnv); err != nil {
 			return fmt.Errorf("failed to run pre-hooks: %w", err)
 		}
 	}
@@ -96,18 +176,83 @@ func runNew(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create genesis.toml
-	if err := s.CreateGenesisConfig(templateURL, version); err != nil {
+	if err := s.CreateGenesisConfig(url, ref, commit); err != nil {
 		return fmt.Errorf("failed to create genesis.toml: %w", err)
 	}
 
 	// Run post-hooks
-	if len(templateConfig.Hooks.Post) > 0 {
+	if len(postHooks) > 0 {
 		fmt.Println("Running post-hooks...")
-		if err := runner.RunHooks(templateConfig.Hooks.Post, projectDir); err != nil {
+		if err := runner.RunHooks(postHooks, projectDir, hookEnv); err != nil {
 			return fmt.Errorf("failed to run post-hooks: %w", err)
 		}
 	}
 
 	fmt.Printf("\nProject %q created successfully!\n", projectName)
 	return nil
-} 
\ No newline at end of file
+}
+
+// givenAnswers collects the variable values given with --data-file, the
+// environment and --data, where later sources take precedence. It also
+// returns the source each value was taken from.
+func givenAnswers(templateConfig *config.TemplateConfig) (map[string]interface{}, map[string]string, error) {
+	given := map[string]interface{}{}
+	sources := map[string]string{}
+
+	if dataFile != "" {
+		values, err := answers.LoadFile(dataFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		for name, value := range values {
+			given[name] = value
+			sources[name] = dataFile
+		}
+	}
+
+	for _, name := range templateConfig.VarNames() {
+		key := varEnvPrefix + runner.EnvName(name)
+		if value, ok := os.LookupEnv(key); ok {
+			given[name] = value
+			sources[name] = key
+		}
+	}
+
+	for _, pair := range dataPairs {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || name == "" {
+			return nil, nil, fmt.Errorf("invalid --data %q, expected key=value", pair)
+		}
+		given[name] = value
+		sources[name] = "--data"
+	}
+
+	return given, sources, nil
+}
+
+// unusedAnswer handles a value given from source for a variable that is
+// not asked for because its when condition does not hold. A value passed
+// with --data is an error, as it was meant for this project; values from
+// a shared answers file or the environment only produce a warning.
+func unusedAnswer(w io.Writer, name, source string) error {
+	switch source {
+	case "":
+		return nil
+	case "--data":
+		return fmt.Errorf("variable %q is set with --data but its condition does not hold", name)
+	}
+	fmt.Fprintf(w, "Warning: ignoring variable %q from %s, its condition does not hold\n", name, source)
+	return nil
+}
+
+// knownAnswers returns the values of variables the template asks for,
+// dropping those of unknown and computed variables
+func knownAnswers(templateConfig *config.TemplateConfig, values map[string]interface{}) map[string]interface{} {
+	known := map[string]interface{}{}
+	for name, value := range values {
+		if v, ok := templateConfig.Vars[name]; ok && v.Value == "" {
+			known[name] = value
+		}
+	}
+	return known
+}
@@ -14,23 +14,6 @@ import (
 )
 
 func setupTestTemplate(t *testing.T) string {
-	// Create a temporary directory for the template
-	templateDir := t.TempDir()
-
-	// Initialize Git repository
-	repo, err := git.PlainInit(templateDir, false)
-	require.NoError(t, err)
-
-	// Configure Git for the test
-	cfg, err := repo.Config()
-	require.NoError(t, err)
-
-	cfg.User.Name = "Test Author"
-	cfg.User.Email = "test@example.com"
-
-	err = repo.SetConfig(cfg)
-	require.NoError(t, err)
-
 	// Create template.toml
 	templateConfig := `version = "1.0"
 
@@ -41,9 +24,6 @@ func setupTestTemplate(t *testing.T) string {
 [hooks]
   post = ["echo 'test' > post-hook.txt"]`
 
-	err = os.WriteFile(filepath.Join(templateDir, "template.toml"), []byte(templateConfig), 0644)
-	require.NoError(t, err)
-
 	// Create a template file
 	mainTemplate := `package main
 
@@ -51,9 +31,41 @@ func main() {
 	println("Hello, {{ .name }}!")
 }`
 
-	err = os.WriteFile(filepath.Join(templateDir, "main.go.tmpl"), []byte(mainTemplate), 0644)
+	return setupTemplateRepo(t, map[string]string{
+		"template.toml": templateConfig,
+		"main.go.tmpl":  mainTemplate,
+	})
+}
+
+// setupTemplateRepo creates a Git repository containing the given files,
+// committed and tagged as v1.0.0
+func setupTemplateRepo(t *testing.T, files map[string]string) string {
+	// Create a temporary directory for the template
+	templateDir := t.TempDir()
+
+	// Initialize Git repository
+	repo, err := git.PlainInit(templateDir, false)
 	require.NoError(t, err)
 
+	// Configure Git for the test
+	cfg, err := repo.Config()
+	require.NoError(t, err)
+
+	cfg.User.Name = "Test Author"
+	cfg.User.Email = "test@example.com"
+
+	err = repo.SetConfig(cfg)
+	require.NoError(t, err)
+
+	// Create the template files
+	for name, content := range files {
+		path := filepath.Join(templateDir, filepath.FromSlash(name))
+		err = os.MkdirAll(filepath.Dir(path), 0755)
+		require.NoError(t, err)
+		err = os.WriteFile(path, []byte(content), 0644)
+		require.NoError(t, err)
+	}
+
 	// Get the worktree
 	w, err := repo.Worktree()
 	require.NoError(t, err)
@@ -84,6 +96,32 @@ func main() {
 	return templateDir
 }
 
+// executeInDir runs the root command with args from within dir and returns
+// its output
+func executeInDir(t *testing.T, dir string, args ...string) (string, error) {
+	currentDir, err := os.Getwd()
+	require.NoError(t, err)
+
+	// Create a deferred function to change back to the original directory
+	defer func() {
+		if err := os.Chdir(currentDir); err != nil {
+			t.Errorf("failed to change back to original directory: %v", err)
+		}
+	}()
+
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("failed to change to directory: %v", err)
+	}
+
+	resetFlags(t)
+	buf := new(bytes.Buffer)
+	rootCmd.SetOut(buf)
+	rootCmd.SetErr(buf)
+	rootCmd.SetArgs(args)
+	err = rootCmd.Execute()
+	return buf.String(), err
+}
+
 func TestNewCommand(t *testing.T) {
 	templateDir := setupTestTemplate(t)
 	projectDir := t.TempDir()
@@ -166,6 +204,7 @@ func TestNewCommand(t *testing.T) {
 			rootCmd.SetErr(buf)
 
 			// Execute command
+			resetFlags(t)
 			rootCmd.SetArgs(tt.args)
 			err = rootCmd.Execute()
 
@@ -233,6 +272,7 @@ func TestNewCommandWithVersion(t *testing.T) {
 	rootCmd.SetErr(buf)
 
 	// Execute command
+	resetFlags(t)
 	rootCmd.SetArgs(args)
 	err = rootCmd.Execute()
 	assert.NoError(t, err)
@@ -253,4 +293,208 @@ func TestNewCommandWithVersion(t *testing.T) {
 		_, err = os.Stat(filepath.Join(projectPath, file))
 		assert.NoError(t, err)
 	}
-} 
\ No newline at end of file
+}
+
+func TestNewCommandHooks(t *testing.T) {
+	templateDir := setupTemplateRepo(t, map[string]string{
+		"template.toml": `version = "1.0"
+
+[vars]
+  name = { prompt = "Enter name:", default = "demo" }
+  go_version = { prompt = "Go version:", default = "1.21" }
+
+[hooks]
+  pre = ["echo \"$GENESIS_PROJECT_NAME $GO_VERSION\" > pre-hook.txt"]
+  post = [
+    "echo \"$NAME $GENESIS_TARGET_DIR\" > post-hook.txt",
+    "test -f \"$GENESIS_TEMPLATE_DIR/template.toml\"",
+    "echo 'Go {{ .go_version }}' > rendered-hook.txt",
+  ]`,
+		"README.md": "# demo\n",
+	})
+	projectDir := t.TempDir()
+
+	_, err := executeInDir(t, projectDir, "new", "test-project", "--template", templateDir, "--yes")
+	require.NoError(t, err)
+
+	projectPath := filepath.Join(projectDir, "test-project")
+	content, err := os.ReadFile(filepath.Join(projectPath, "pre-hook.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "test-project 1.21\n", string(content))
+
+	realProjectPath, err := filepath.EvalSymlinks(projectPath)
+	require.NoError(t, err)
+	content, err = os.ReadFile(filepath.Join(projectPath, "post-hook.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "demo "+realProjectPath+"\n", string(content))
+
+	// Hook commands are rendered with the template variables
+	content, err = os.ReadFile(filepath.Join(projectPath, "rendered-hook.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "Go 1.21\n", string(content))
+}
+
+func TestNewCommandTypedVariables(t *testing.T) {
+	templateDir := setupTemplateRepo(t, map[string]string{
+		"template.toml": `version = "1.0"
+
+[vars]
+  use_docker = { prompt = "Use Docker?", type = "bool", default = true }
+  features = { prompt = "Features:", type = "multiselect", choices = ["ci", "docs", "lint"], default = ["ci", "lint"] }
+
+[hooks]
+  post = ["echo \"$USE_DOCKER $FEATURES\" > hook.txt"]`,
+		"README.md.tmpl": "{{ if .use_docker }}Docker{{ end }}{{ range .features }} {{ . }}{{ end }}\n",
+	})
+	projectDir := t.TempDir()
+
+	_, err := executeInDir(t, projectDir, "new", "test-project", "--template", templateDir, "--yes")
+	require.NoError(t, err)
+
+	projectPath := filepath.Join(projectDir, "test-project")
+	content, err := os.ReadFile(filepath.Join(projectPath, "README.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "Docker ci lint\n", string(content))
+
+	content, err = os.ReadFile(filepath.Join(projectPath, "hook.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "true ci,lint\n", string(content))
+}
+
+func TestNewCommandGivenAnswers(t *testing.T) {
+	templateDir := setupTemplateRepo(t, map[string]string{
+		"template.toml": `version = "1.0"
+
+[vars]
+  name = { prompt = "Enter name:", default = "demo" }
+  owner = { prompt = "Owner:", default = "acme" }
+  port = { prompt = "Port:", type = "int", default = 8080 }
+  version = { prompt = "Version:", default = "0.1.0", regex = "^\\d+\\.\\d+\\.\\d+$" }
+  docker = { prompt = "Docker?", type = "bool", default = false }
+  registry = { prompt = "Registry:", default = "docker.io", when = "{{ .docker }}" }`,
+		"README.md.tmpl": "{{ .owner }}/{{ .name }}:{{ .port }} {{ .version }}\n",
+	})
+	projectDir := t.TempDir()
+
+	answersFile := filepath.Join(t.TempDir(), "answers.yaml")
+	err := os.WriteFile(answersFile, []byte("name: from-file\nowner: file-owner\nport: 3000\n"), 0644)
+	require.NoError(t, err)
+	t.Setenv("GENESIS_VAR_OWNER", "env-owner")
+	t.Setenv("GENESIS_VAR_PORT", "4000")
+	t.Setenv("GENESIS_VAR_REGISTRY", "quay.io")
+
+	// --data overrides the environment, which overrides the file
+	output, err := executeInDir(t, projectDir, "new", "test-project", "--template", templateDir, "--yes",
+		"--data-file", answersFile, "--data", "port=5000", "--data", "version=1.2.3")
+	require.NoError(t, err)
+
+	// An environment value for a variable whose condition does not hold
+	// only produces a warning, as the environment may be shared
+	assert.Contains(t, output, `Warning: ignoring variable "registry" from GENESIS_VAR_REGISTRY, its condition does not hold`)
+
+	content, err := os.ReadFile(filepath.Join(projectDir, "test-project", "README.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "env-owner/from-file:5000 1.2.3\n", string(content))
+
+	// Given values are validated against the variable's regex
+	_, err = executeInDir(t, projectDir, "new", "other-project", "--template", templateDir, "--yes",
+		"--data", "version=latest")
+	assert.ErrorContains(t, err, `invalid value for variable "version"`)
+
+	_, err = executeInDir(t, projectDir, "new", "other-project", "--template", templateDir, "--yes",
+		"--data", "version")
+	assert.ErrorContains(t, err, `invalid --data "version"`)
+
+	// An explicit --data value for such a variable is an error
+	_, err = executeInDir(t, projectDir, "new", "other-project", "--template", templateDir, "--yes",
+		"--data", "registry=ghcr.io")
+	assert.ErrorContains(t, err, `variable "registry" is set with --data but its condition does not hold`)
+
+	_, err = executeInDir(t, projectDir, "new", "docker-project", "--template", templateDir, "--yes",
+		"--data", "docker=true", "--data", "registry=ghcr.io")
+	assert.NoError(t, err)
+}
+
+func TestNewCommandReplay(t *testing.T) {
+	templateDir := setupTemplateRepo(t, map[string]string{
+		"template.toml": `version = "1.0"
+
+[vars]
+  name = { prompt = "Enter name:", default = "demo" }
+  port = { prompt = "Port:", type = "int", default = 8080 }
+  token = { prompt = "Token:", default = "none", secret = true }
+  slug = { value = "{{ .name | lower }}" }`,
+		"README.md.tmpl": "{{ .slug }}:{{ .port }} {{ .token }}\n",
+	})
+	projectDir := t.TempDir()
+
+	_, err := executeInDir(t, projectDir, "new", "original", "--template", templateDir, "--version", "v1.0.0", "--yes",
+		"--data", "name=Shop", "--data", "port=9090", "--data", "token=s3cret")
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(projectDir, "original", "genesis.toml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "[project.answers]\n  name = \"Shop\"\n  port = 9090\n")
+	assert.NotContains(t, string(content), "s3cret")
+	assert.NotContains(t, string(content), "slug")
+
+	// The replayed project uses the recorded template and answers; the
+	// secret variable is not recorded and takes its default
+	_, err = executeInDir(t, projectDir, "new", "replayed", "--replay", "original", "--yes")
+	require.NoError(t, err)
+
+	content, err = os.ReadFile(filepath.Join(projectDir, "replayed", "README.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "shop:9090 none\n", string(content))
+
+	original, err := os.ReadFile(filepath.Join(projectDir, "original", "genesis.toml"))
+	require.NoError(t, err)
+	replayed, err := os.ReadFile(filepath.Join(projectDir, "replayed", "genesis.toml"))
+	require.NoError(t, err)
+	assert.Equal(t, string(original), string(replayed))
+
+	// Explicitly given answers override the recorded ones
+	_, err = executeInDir(t, projectDir, "new", "changed", "--replay", "original", "--yes", "--data", "port=3000")
+	require.NoError(t, err)
+
+	content, err = os.ReadFile(filepath.Join(projectDir, "changed", "README.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "shop:3000 none\n", string(content))
+
+	_, err = executeInDir(t, projectDir, "new", "missing", "--replay", "does-not-exist", "--yes")
+	assert.ErrorContains(t, err, "failed to read project to replay")
+}
+
+func TestNewCommandLocalDirectory(t *testing.T) {
+	// A plain directory that is not a Git repository
+	templateDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(templateDir, "template.toml"), []byte(`version = "1.0"
+
+[vars]
+  name = { prompt = "Enter name:", default = "demo" }`), 0644)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(templateDir, "README.md.tmpl"), []byte("# {{ .name }}\n"), 0644)
+	require.NoError(t, err)
+	projectDir := t.TempDir()
+
+	_, err = executeInDir(t, projectDir, "new", "test-project", "--template", "file://"+filepath.ToSlash(templateDir), "--yes")
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(projectDir, "test-project", "README.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# demo\n", string(content))
+
+	// No commit is recorded for local directories, so there is nothing to
+	// compare the project with or update it from
+	content, err = os.ReadFile(filepath.Join(projectDir, "test-project", "genesis.toml"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "template_commit")
+
+	for _, command := range []string{"diff", "update"} {
+		_, err = executeInDir(t, filepath.Join(projectDir, "test-project"), command)
+		assert.ErrorContains(t, err, "records no commit to compare with", command)
+	}
+
+	// The template directory itself is left alone
+	assert.FileExists(t, filepath.Join(templateDir, "README.md.tmpl"))
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/felipevolpatto/genesis/internal/answers"
+	"github.com/felipevolpatto/genesis/internal/config"
+	"github.com/felipevolpatto/genesis/internal/scaffolder"
+)
+
+// renderedTemplate is a template rendered into a temporary directory
+type renderedTemplate struct {
+	dir       string
+	commit    string
+	config    *config.TemplateConfig
+	variables map[string]interface{}
+}
+
+// renderTemplate fetches the template at the given revision and renders it
+// into a temporary directory without running its hooks. Variables are
+// taken from given where the template asks for them and obtained from ask
+// otherwise. The caller removes the directory.
+func renderTemplate(url, revision string, given map[string]interface{}, ask answers.AskFunc) (*renderedTemplate, error) {
+	templateDir, commit, err := scaffolder.FetchTemplate(url, revision)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch template: %w", err)
+	}
+	defer scaffolder.CleanupTemplate(templateDir)
+
+	templateConfig, err := config.ParseTemplateConfig(filepath.Join(templateDir, "template.toml"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template config: %w", err)
+	}
+
+	ask, err = answers.Given(templateConfig.Vars, knownAnswers(templateConfig, given), ask)
+	if err != nil {
+		return nil, fmt.Errorf("invalid answers: %w", err)
+	}
+	variables, err := answers.Resolve(templateConfig.Vars, templateConfig.VarNames(), ask)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get variable values: %w", err)
+	}
+
+	dir, err := os.MkdirTemp("", "genesis-render-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	if err := scaffolder.New(templateDir, dir, variables, templateConfig).Scaffold(); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("failed to render template: %w", err)
+	}
+
+	return &renderedTemplate{dir: dir, commit: commit, config: templateConfig, variables: variables}, nil
+}
+
+// recordedRevision returns the revision of the template a project was
+// generated from. A local template copied as a working copy records no
+// commit, so the template the project was generated from cannot be
+// rendered again and an error is returned.
+func recordedRevision(project config.Project) (string, error) {
+	revision := project.Revision()
+	if revision == "" && scaffolder.IsLocal(project.TemplateURL) {
+		return "", fmt.Errorf("the project was generated from the working copy of %s, which records no commit to compare with; generate it with --version to record one", project.TemplateURL)
+	}
+	return revision, nil
+}
@@ -4,9 +4,38 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+// resetFlags restores the flags of every command, and the variables they
+// are bound to, to their defaults. Commands are shared between test cases,
+// so values set by one execution would otherwise leak into the next.
+func resetFlags(t *testing.T) {
+	t.Helper()
+
+	var reset func(c *cobra.Command)
+	reset = func(c *cobra.Command) {
+		c.Flags().VisitAll(func(f *pflag.Flag) {
+			if v, ok := f.Value.(pflag.SliceValue); ok {
+				require.NoError(t, v.Replace(nil))
+			} else {
+				require.NoError(t, f.Value.Set(f.DefValue))
+			}
+			f.Changed = false
+		})
+		// The flag set also remembers where "--" was seen
+		c.Flags().Init(c.Name(), pflag.ContinueOnError)
+
+		for _, sub := range c.Commands() {
+			reset(sub)
+		}
+	}
+	reset(rootCmd)
+}
+
 func TestRootCommand(t *testing.T) {
 	// Create a buffer to capture output
 	buf := new(bytes.Buffer)
@@ -14,6 +43,7 @@ func TestRootCommand(t *testing.T) {
 	rootCmd.SetErr(buf)
 
 	// Test help output
+	resetFlags(t)
 	rootCmd.SetArgs([]string{"--help"})
 	err := rootCmd.Execute()
 	assert.NoError(t, err)
@@ -33,10 +63,11 @@ func TestRootCommandNoArgs(t *testing.T) {
 	rootCmd.SetErr(buf)
 
 	// Test with no arguments
+	resetFlags(t)
 	rootCmd.SetArgs([]string{})
 	err := rootCmd.Execute()
 	assert.NoError(t, err)
 
 	output := buf.String()
 	assert.Contains(t, output, "Available Commands:")
-} 
\ No newline at end of file
+}
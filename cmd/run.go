@@ -2,32 +2,79 @@ package cmd
 
 import (
 	"fmt"
+	"path/filepath"
+	"time"
 
 	"github.com/felipevolpatto/genesis/internal/config"
 	"github.com/felipevolpatto/genesis/internal/runner"
 	"github.com/spf13/cobra"
 )
 
+var (
+	jobs  int
+	color bool
+	force bool
+	watch bool
+
+	continueOnError bool
+	gracePeriod     time.Duration
+)
+
 func init() {
 	runCmd := &cobra.Command{
-		Use:   "run [task-name]",
-		Short: "Run a task defined in genesis.toml",
-		Long: `Run a task defined in genesis.toml.
+		Use:   "run [task-name...] [-- args...]",
+		Short: "Run tasks defined in genesis.toml",
+		Long: `Run one or more tasks defined in genesis.toml.
 
 Tasks are defined in the project's genesis.toml file under the [tasks] section.
-Use 'run list' to see all available tasks.`,
+Use 'run list' to see all available tasks.
+
+Tasks run in the order given, after their dependencies, and the run stops at
+the first failure. With --continue-on-error every task that does not depend on
+a failed one still runs, and a summary of all results is printed at the end.
+
+With --jobs greater than one, independent tasks run concurrently and each
+line of their output is prefixed with the task name.
+
+Tasks that declare sources are skipped when neither their sources nor their
+command changed since the last successful run. Use --force to run them anyway.
+
+With --watch, the task runs again whenever one of its source files changes.
+
+Arguments after "--" are passed to the named tasks, either in place of a
+{{.CLI_ARGS}} placeholder in the task command or appended to it:
+
+  genesis run test -- -run TestFoo -v
+
+Each task runs in its own process group. Ctrl-C and SIGTERM are forwarded to
+every process of a running task, which is killed if it has not exited after
+--grace-period.`,
 		Args: cobra.MinimumNArgs(1),
 		RunE: runTask,
 	}
 
+	runCmd.Flags().IntVarP(&jobs, "jobs", "j", 1, "Number of tasks to run in parallel")
+	runCmd.Flags().BoolVar(&color, "color", false, "Color task name prefixes in parallel output")
+	runCmd.Flags().BoolVarP(&force, "force", "f", false, "Run tasks even if they are up to date")
+	runCmd.Flags().BoolVarP(&watch, "watch", "w", false, "Re-run the task whenever its sources change")
+	runCmd.Flags().BoolVar(&continueOnError, "continue-on-error", false, "Keep running remaining tasks after a failure and print a summary")
+	runCmd.Flags().DurationVar(&gracePeriod, "grace-period", runner.DefaultGracePeriod, "Time interrupted tasks have to exit before they are killed")
+
 	rootCmd.AddCommand(runCmd)
 }
 
 func runTask(cmd *cobra.Command, args []string) error {
-	taskName := args[0]
+	// Split task names from the arguments passed through to them
+	var taskArgs []string
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+		args, taskArgs = args[:dash], args[dash:]
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("requires at least one task name")
+	}
 
 	// Handle list command
-	if taskName == "list" {
+	if args[0] == "list" {
 		return listTasks(cmd)
 	}
 
@@ -42,19 +89,36 @@ func runTask(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to parse genesis.toml: %w", err)
 	}
 
-	// Find the task
-	task, ok := projectConfig.Tasks[taskName]
-	if !ok {
-		return fmt.Errorf("task %q not found", taskName)
+	if jobs < 1 {
+		return fmt.Errorf("--jobs must be at least 1")
 	}
 
-	// Run the task
-	r := runner.New()
-	if err := r.RunTask(task); err != nil {
-		return fmt.Errorf("failed to run task %q: %w", taskName, err)
+	if watch && len(args) != 1 {
+		return fmt.Errorf("--watch requires exactly one task")
 	}
 
-	return nil
+	// Run the tasks and their dependencies
+	r := runner.New(
+		runner.WithJobs(jobs),
+		runner.WithColor(color),
+		runner.WithForce(force),
+		runner.WithArgs(taskArgs),
+		runner.WithContinueOnError(continueOnError),
+		runner.WithGracePeriod(gracePeriod),
+		runner.WithDir(filepath.Dir(configPath)),
+		runner.WithCacheDir(filepath.Join(filepath.Dir(configPath), ".genesis")),
+		runner.WithOutput(cmd.OutOrStdout(), cmd.ErrOrStderr()),
+	)
+
+	// Forward Ctrl-C and SIGTERM to running tasks
+	ctx, stop := runner.InterruptContext(cmd.Context())
+	defer stop()
+
+	if watch {
+		return r.Watch(ctx, projectConfig.Tasks, args[0])
+	}
+
+	return r.Run(ctx, projectConfig.Tasks, args...)
 }
 
 func listTasks(cmd *cobra.Command) error {
@@ -76,4 +140,4 @@ func listTasks(cmd *cobra.Command) error {
 	}
 
 	return nil
-} 
\ No newline at end of file
+}
@@ -2,9 +2,11 @@ package cmd
 
 import (
 	"bytes"
+	"errors"
 	"os"
 	"testing"
 
+	"github.com/felipevolpatto/genesis/internal/runner"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -24,7 +26,7 @@ func TestRunCommand(t *testing.T) {
 [tasks]
   test = { description = "Run tests", cmd = "echo 'test'" }`,
 			expectError: false,
-			setup:      func(t *testing.T) error { return nil },
+			setup:       func(t *testing.T) error { return nil },
 		},
 		{
 			name: "list task",
@@ -33,7 +35,7 @@ func TestRunCommand(t *testing.T) {
 [tasks]
   test = { description = "Run tests", cmd = "echo 'test'" }`,
 			expectError: false,
-			setup:      func(t *testing.T) error { return nil },
+			setup:       func(t *testing.T) error { return nil },
 		},
 		{
 			name: "invalid task",
@@ -42,7 +44,7 @@ func TestRunCommand(t *testing.T) {
 [tasks]
   test = { description = "Run tests", cmd = "echo 'test'" }`,
 			expectError: true,
-			setup:      func(t *testing.T) error { return nil },
+			setup:       func(t *testing.T) error { return nil },
 		},
 		{
 			name: "nonexistent task",
@@ -51,14 +53,35 @@ func TestRunCommand(t *testing.T) {
 [tasks]
   test = { description = "Run tests", cmd = "echo 'test'" }`,
 			expectError: true,
-			setup:      func(t *testing.T) error { return nil },
+			setup:       func(t *testing.T) error { return nil },
+		},
+		{
+			name: "task with dependencies",
+			args: []string{"run", "check"},
+			config: `version = "1.0"
+[tasks]
+  lint = { description = "Run linters", cmd = "echo 'lint'" }
+  test = { description = "Run tests", cmd = "echo 'test'" }
+  check = { description = "Run all checks", deps = ["lint", "test"] }`,
+			expectError: false,
+			setup:       func(t *testing.T) error { return nil },
+		},
+		{
+			name: "dependency cycle",
+			args: []string{"run", "a"},
+			config: `version = "1.0"
+[tasks]
+  a = { description = "A", cmd = "echo 'a'", deps = ["b"] }
+  b = { description = "B", cmd = "echo 'b'", deps = ["a"] }`,
+			expectError: true,
+			setup:       func(t *testing.T) error { return nil },
 		},
 		{
 			name:        "missing task name",
 			args:        []string{"run"},
 			config:      `version = "1.0"`,
 			expectError: true,
-			setup:      func(t *testing.T) error { return nil },
+			setup:       func(t *testing.T) error { return nil },
 		},
 	}
 
@@ -97,6 +120,7 @@ func TestRunCommand(t *testing.T) {
 			rootCmd.SetErr(buf)
 
 			// Execute command
+			resetFlags(t)
 			rootCmd.SetArgs(tt.args)
 			err = rootCmd.Execute()
 
@@ -136,6 +160,7 @@ func TestRunCommandNoConfig(t *testing.T) {
 	rootCmd.SetErr(buf)
 
 	// Execute command
+	resetFlags(t)
 	rootCmd.SetArgs([]string{"run", "test"})
 	err = rootCmd.Execute()
 	assert.Error(t, err)
@@ -176,10 +201,305 @@ func TestRunCommandListTasks(t *testing.T) {
 	rootCmd.SetErr(buf)
 
 	// Execute command
+	resetFlags(t)
 	rootCmd.SetArgs([]string{"run", "list"})
 	err = rootCmd.Execute()
 	assert.NoError(t, err)
 	assert.Contains(t, buf.String(), "Available tasks:")
 	assert.Contains(t, buf.String(), "test")
 	assert.Contains(t, buf.String(), "build")
-} 
\ No newline at end of file
+}
+
+func TestRunCommandParallel(t *testing.T) {
+	// Create a temporary directory
+	tempDir := t.TempDir()
+
+	// Save current directory
+	currentDir, err := os.Getwd()
+	require.NoError(t, err)
+
+	// Create a deferred function to change back to the original directory
+	// and reset the flags shared between test cases
+	defer func() {
+		jobs = 1
+		if err := os.Chdir(currentDir); err != nil {
+			t.Errorf("failed to change back to original directory: %v", err)
+		}
+	}()
+
+	// Change to the temporary directory
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("failed to change to temporary directory: %v", err)
+	}
+
+	// Create genesis.toml
+	config := `version = "1.0"
+[tasks]
+  lint = { description = "Run linters", cmd = "echo 'linting'" }
+  test = { description = "Run tests", cmd = "echo 'testing'" }`
+
+	err = os.WriteFile("genesis.toml", []byte(config), 0644)
+	require.NoError(t, err)
+
+	// Create a buffer to capture output
+	buf := new(bytes.Buffer)
+	rootCmd.SetOut(buf)
+	rootCmd.SetErr(buf)
+
+	// Execute command
+	resetFlags(t)
+	rootCmd.SetArgs([]string{"run", "--jobs", "2", "lint", "test"})
+	err = rootCmd.Execute()
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "[lint] linting")
+	assert.Contains(t, buf.String(), "[test] testing")
+}
+
+func TestRunCommandUpToDate(t *testing.T) {
+	// Create a temporary directory
+	tempDir := t.TempDir()
+
+	// Save current directory
+	currentDir, err := os.Getwd()
+	require.NoError(t, err)
+
+	// Create a deferred function to change back to the original directory
+	defer func() {
+		if err := os.Chdir(currentDir); err != nil {
+			t.Errorf("failed to change back to original directory: %v", err)
+		}
+	}()
+
+	// Change to the temporary directory
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("failed to change to temporary directory: %v", err)
+	}
+
+	// Create genesis.toml and a source file
+	config := `version = "1.0"
+[tasks]
+  build = { description = "Build", cmd = "echo 'building'", sources = ["*.txt"] }`
+
+	err = os.WriteFile("genesis.toml", []byte(config), 0644)
+	require.NoError(t, err)
+	err = os.WriteFile("input.txt", []byte("input"), 0644)
+	require.NoError(t, err)
+
+	run := func(args ...string) string {
+		buf := new(bytes.Buffer)
+		rootCmd.SetOut(buf)
+		rootCmd.SetErr(buf)
+		resetFlags(t)
+		rootCmd.SetArgs(args)
+		require.NoError(t, rootCmd.Execute())
+		return buf.String()
+	}
+
+	// First run executes the task
+	assert.Contains(t, run("run", "build"), "building")
+
+	// Second run skips it
+	output := run("run", "build")
+	assert.Contains(t, output, "task build is up to date")
+	assert.NotContains(t, output, "building")
+
+	// --force runs it anyway
+	assert.Contains(t, run("run", "--force", "build"), "building")
+}
+
+func TestRunCommandWatchErrors(t *testing.T) {
+	// Create a temporary directory
+	tempDir := t.TempDir()
+
+	// Save current directory
+	currentDir, err := os.Getwd()
+	require.NoError(t, err)
+
+	// Create a deferred function to change back to the original directory
+	defer func() {
+		if err := os.Chdir(currentDir); err != nil {
+			t.Errorf("failed to change back to original directory: %v", err)
+		}
+	}()
+
+	// Change to the temporary directory
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("failed to change to temporary directory: %v", err)
+	}
+
+	// Create genesis.toml
+	config := `version = "1.0"
+[tasks]
+  lint = { description = "Run linters", cmd = "echo 'linting'" }
+  test = { description = "Run tests", cmd = "echo 'testing'", sources = ["**/*.go"] }`
+
+	err = os.WriteFile("genesis.toml", []byte(config), 0644)
+	require.NoError(t, err)
+
+	tests := []struct {
+		args     []string
+		contains string
+	}{
+		{[]string{"run", "--watch", "lint", "test"}, "--watch requires exactly one task"},
+		{[]string{"run", "--watch", "lint"}, "no sources to watch"},
+	}
+
+	for _, tt := range tests {
+		// Create a buffer to capture output
+		buf := new(bytes.Buffer)
+		rootCmd.SetOut(buf)
+		rootCmd.SetErr(buf)
+
+		// Execute command
+		resetFlags(t)
+		rootCmd.SetArgs(tt.args)
+		err = rootCmd.Execute()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), tt.contains)
+	}
+}
+
+func TestRunCommandPassesArgs(t *testing.T) {
+	// Create a temporary directory
+	tempDir := t.TempDir()
+
+	// Save current directory
+	currentDir, err := os.Getwd()
+	require.NoError(t, err)
+
+	// Create a deferred function to change back to the original directory
+	defer func() {
+		if err := os.Chdir(currentDir); err != nil {
+			t.Errorf("failed to change back to original directory: %v", err)
+		}
+	}()
+
+	// Change to the temporary directory
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("failed to change to temporary directory: %v", err)
+	}
+
+	// Create genesis.toml
+	config := `version = "1.0"
+[tasks]
+  greet = { description = "Greet", cmd = "echo hello" }`
+
+	err = os.WriteFile("genesis.toml", []byte(config), 0644)
+	require.NoError(t, err)
+
+	// Create a buffer to capture output
+	buf := new(bytes.Buffer)
+	rootCmd.SetOut(buf)
+	rootCmd.SetErr(buf)
+
+	// Execute command
+	resetFlags(t)
+	rootCmd.SetArgs([]string{"run", "greet", "--", "big", "-v"})
+	err = rootCmd.Execute()
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "hello big -v")
+
+	// Arguments without a task name are rejected
+	resetFlags(t)
+	rootCmd.SetArgs([]string{"run", "--", "-v"})
+	err = rootCmd.Execute()
+	assert.Error(t, err)
+}
+
+func TestRunCommandContinueOnError(t *testing.T) {
+	// Create a temporary directory
+	tempDir := t.TempDir()
+
+	// Save current directory
+	currentDir, err := os.Getwd()
+	require.NoError(t, err)
+
+	// Create a deferred function to change back to the original directory
+	defer func() {
+		if err := os.Chdir(currentDir); err != nil {
+			t.Errorf("failed to change back to original directory: %v", err)
+		}
+	}()
+
+	// Change to the temporary directory
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("failed to change to temporary directory: %v", err)
+	}
+
+	// Create genesis.toml
+	config := `version = "1.0"
+[tasks]
+  lint = { description = "Run linters", cmd = "exit 1" }
+  test = { description = "Run tests", cmd = "echo 'testing'" }`
+
+	err = os.WriteFile("genesis.toml", []byte(config), 0644)
+	require.NoError(t, err)
+
+	// Without the flag the run stops at the first failure
+	buf := new(bytes.Buffer)
+	rootCmd.SetOut(buf)
+	rootCmd.SetErr(buf)
+	resetFlags(t)
+	rootCmd.SetArgs([]string{"run", "lint", "test"})
+	err = rootCmd.Execute()
+	assert.Error(t, err)
+	assert.NotContains(t, buf.String(), "testing")
+
+	// With the flag every task runs and a summary is printed
+	buf = new(bytes.Buffer)
+	rootCmd.SetOut(buf)
+	rootCmd.SetErr(buf)
+	resetFlags(t)
+	rootCmd.SetArgs([]string{"run", "--continue-on-error", "lint", "test"})
+	err = rootCmd.Execute()
+	assert.Error(t, err)
+	assert.Contains(t, buf.String(), "testing")
+	assert.Contains(t, buf.String(), "TASK")
+	assert.Regexp(t, `lint\s+failed`, buf.String())
+	assert.Regexp(t, `test\s+passed`, buf.String())
+}
+
+func TestRunCommandExitCode(t *testing.T) {
+	// Create a temporary directory
+	tempDir := t.TempDir()
+
+	// Save current directory
+	currentDir, err := os.Getwd()
+	require.NoError(t, err)
+
+	// Create a deferred function to change back to the original directory
+	defer func() {
+		if err := os.Chdir(currentDir); err != nil {
+			t.Errorf("failed to change back to original directory: %v", err)
+		}
+	}()
+
+	// Change to the temporary directory
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("failed to change to temporary directory: %v", err)
+	}
+
+	// Create genesis.toml
+	config := `version = "1.0"
+[tasks]
+  test = { description = "Run tests", cmd = "exit 3" }`
+
+	err = os.WriteFile("genesis.toml", []byte(config), 0644)
+	require.NoError(t, err)
+
+	// Create a buffer to capture output
+	buf := new(bytes.Buffer)
+	rootCmd.SetOut(buf)
+	rootCmd.SetErr(buf)
+
+	// Execute command
+	resetFlags(t)
+	rootCmd.SetArgs([]string{"run", "test"})
+	err = rootCmd.Execute()
+	require.Error(t, err)
+
+	// The task's exit status is available to main
+	var exitErr *runner.ExitError
+	require.True(t, errors.As(err, &exitErr))
+	assert.Equal(t, 3, exitErr.Code)
+}
@@ -4,9 +4,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"text/template"
+	"strings"
 
 	"github.com/felipevolpatto/genesis/internal/config"
+	"github.com/felipevolpatto/genesis/internal/render"
 	"github.com/spf13/cobra"
 )
 
@@ -91,6 +92,13 @@ func validateTemplate(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
+		// Check templated file and directory names
+		if strings.Contains(info.Name(), "{{") {
+			if _, err := render.Parse(info.Name(), info.Name()); err != nil {
+				return fmt.Errorf("invalid template syntax in name of %s: %w", path, err)
+			}
+		}
+
 		// Skip directories and non-.tmpl files
 		if info.IsDir() || filepath.Ext(path) != ".tmpl" {
 			return nil
@@ -102,7 +110,7 @@ func validateTemplate(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("failed to read template file %s: %w", path, err)
 		}
 
-		_, err = template.New(filepath.Base(path)).Parse(string(content))
+		_, err = render.Parse(filepath.Base(path), string(content))
 		if err != nil {
 			return fmt.Errorf("invalid template syntax in %s: %w", path, err)
 		}
@@ -116,12 +124,19 @@ func validateTemplate(cmd *cobra.Command, args []string) error {
 
 	fmt.Fprintf(cmd.OutOrStdout(), "Template in %s is valid\n", templatePath)
 	fmt.Fprintf(cmd.OutOrStdout(), "Variables defined:\n")
-	for name, v := range templateConfig.Vars {
-		fmt.Fprintf(cmd.OutOrStdout(), "  %s\n    Prompt: %s\n    Default: %s\n", name, v.Prompt, v.Default)
+	for _, name := range templateConfig.VarNames() {
+		v := templateConfig.Vars[name]
+		fmt.Fprintf(cmd.OutOrStdout(), "  %s\n    Prompt: %s\n    Type: %s\n    Default: %v\n", name, v.Prompt, v.Kind(), v.Default)
+		if len(v.Choices) > 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "    Choices: %s\n", strings.Join(v.Choices, ", "))
+		}
+		if v.Value != "" {
+			fmt.Fprintf(cmd.OutOrStdout(), "    Value: %s\n", v.Value)
+		}
 		if v.Regex != "" {
 			fmt.Fprintf(cmd.OutOrStdout(), "    Validation: %s\n", v.Regex)
 		}
 	}
 
 	return nil
-} 
\ No newline at end of file
+}
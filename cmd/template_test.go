@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -118,6 +120,7 @@ func TestTemplateCommand(t *testing.T) {
 			rootCmd.SetErr(buf)
 
 			// Execute command
+			resetFlags(t)
 			rootCmd.SetArgs(tt.args)
 			err = rootCmd.Execute()
 
@@ -170,6 +173,7 @@ func TestTemplateValidateWithInvalidConfig(t *testing.T) {
 	rootCmd.SetErr(buf)
 
 	// Execute command
+	resetFlags(t)
 	rootCmd.SetArgs([]string{"template", "validate", "."})
 	err = rootCmd.Execute()
 	assert.Error(t, err)
@@ -214,8 +218,97 @@ func TestTemplateValidateWithInvalidTemplateFile(t *testing.T) {
 	rootCmd.SetErr(buf)
 
 	// Execute command
+	resetFlags(t)
 	rootCmd.SetArgs([]string{"template", "validate", "."})
 	err = rootCmd.Execute()
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid template syntax")
-} 
\ No newline at end of file
+}
+
+func TestTemplateValidateWithInvalidHook(t *testing.T) {
+	tempDir := t.TempDir()
+
+	templateConfig := `version = "1.0"
+[hooks]
+  pre = ["echo ok"]
+  post = ["git commit -m 'Init {{ .name'"]`
+
+	err := os.WriteFile(filepath.Join(tempDir, "template.toml"), []byte(templateConfig), 0644)
+	require.NoError(t, err)
+
+	_, err = executeInDir(t, tempDir, "template", "validate", ".")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid template syntax in post-hook")
+}
+
+func TestTemplateValidateWithUndefinedHookVariable(t *testing.T) {
+	tempDir := t.TempDir()
+
+	templateConfig := `version = "1.0"
+[vars]
+  name = { prompt = "Name:", default = "demo" }
+[hooks]
+  post = ["echo {{ .nmae }} > hook.txt"]`
+
+	err := os.WriteFile(filepath.Join(tempDir, "template.toml"), []byte(templateConfig), 0644)
+	require.NoError(t, err)
+
+	_, err = executeInDir(t, tempDir, "template", "validate", ".")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid post-hook "echo {{ .nmae }} > hook.txt": undefined variable "nmae"`)
+}
+
+func TestTemplateValidateWithInvalidFileName(t *testing.T) {
+	tempDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tempDir, "template.toml"), []byte(`version = "1.0"`), 0644)
+	require.NoError(t, err)
+	err = os.MkdirAll(filepath.Join(tempDir, "{{ .package"), 0755)
+	require.NoError(t, err)
+
+	_, err = executeInDir(t, tempDir, "template", "validate", ".")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid template syntax in name of")
+}
+
+func TestTemplateValidateWithInvalidFileRule(t *testing.T) {
+	tempDir := t.TempDir()
+
+	templateConfig := `version = "1.0"
+[files]
+  rules = [{ path = "docker/**", when = "{{ eq .docker" }]`
+
+	err := os.WriteFile(filepath.Join(tempDir, "template.toml"), []byte(templateConfig), 0644)
+	require.NoError(t, err)
+
+	_, err = executeInDir(t, tempDir, "template", "validate", ".")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid template syntax in condition for docker/**")
+}
+
+func TestTemplateValidateListsVariablesInOrder(t *testing.T) {
+	tempDir := t.TempDir()
+
+	templateConfig := `version = "1.0"
+[vars]
+  name = { prompt = "Name:" }
+  module = { prompt = "Module path:" }
+  author = { prompt = "Author:" }
+  license = { prompt = "License:", type = "choice", choices = ["MIT", "Apache-2.0"] }`
+
+	err := os.WriteFile(filepath.Join(tempDir, "template.toml"), []byte(templateConfig), 0644)
+	require.NoError(t, err)
+
+	output, err := executeInDir(t, tempDir, "template", "validate", ".")
+	require.NoError(t, err)
+
+	positions := []int{
+		strings.Index(output, "Prompt: Name:"),
+		strings.Index(output, "Prompt: Module path:"),
+		strings.Index(output, "Prompt: Author:"),
+		strings.Index(output, "Prompt: License:"),
+	}
+	assert.True(t, sort.IntsAreSorted(positions), output)
+	assert.NotContains(t, positions, -1)
+	assert.Contains(t, output, "Choices: MIT, Apache-2.0")
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/felipevolpatto/genesis/internal/answers"
+	"github.com/felipevolpatto/genesis/internal/config"
+	"github.com/felipevolpatto/genesis/internal/scaffolder"
+	"github.com/felipevolpatto/genesis/internal/tui"
+	"github.com/felipevolpatto/genesis/internal/update"
+	"github.com/go-git/go-git/v5"
+	"github.com/spf13/cobra"
+)
+
+var (
+	updateVersion string
+	updateYes     bool
+	allowDirty    bool
+)
+
+func init() {
+	updateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update the project to a newer version of its template",
+		Long: `Update the project to a newer version of its template.
+
+The template recorded in genesis.toml is rendered at the recorded commit (or
+version, for projects that do not record one) and at the target version with
+the recorded answers, and the changes between the two are applied to the
+project as a three-way merge. Files the project did not modify are replaced;
+where both the project and the template changed the same lines, conflict
+markers are left in the file (or, for binary files and files the project
+deleted, the template version is written next to it with a .rej suffix).
+Template hooks are not run.
+
+Variables added to the template are prompted for, or take their defaults
+with --yes. The update refuses to run in a git worktree with uncommitted
+changes unless --allow-dirty is given.`,
+		Args: cobra.NoArgs,
+		RunE: runUpdate,
+	}
+
+	updateCmd.Flags().StringVarP(&updateVersion, "version", "v", "", "Template version to update to (tag, branch, or commit hash; default branch if empty)")
+	updateCmd.Flags().BoolVarP(&updateYes, "yes", "y", false, "Skip prompts for new variables and use their default values")
+	updateCmd.Flags().BoolVar(&allowDirty, "allow-dirty", false, "Update even if the project has uncommitted changes")
+
+	rootCmd.AddCommand(updateCmd)
+}
+
+func runUpdate(cmd *cobra.Command, args []string) error {
+	// Find and parse genesis.toml
+	configPath, err := config.FindProjectConfig()
+	if err != nil {
+		return fmt.Errorf("failed to find genesis.toml: %w", err)
+	}
+	projectDir := filepath.Dir(configPath)
+
+	projectConfig, err := config.ParseProjectConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to parse genesis.toml: %w", err)
+	}
+	project := projectConfig.Project
+	if project.TemplateURL == "" {
+		return fmt.Errorf("genesis.toml does not record a template_url")
+	}
+	revision, err := recordedRevision(project)
+	if err != nil {
+		return err
+	}
+
+	if !allowDirty {
+		if err := checkClean(projectDir); err != nil {
+			return err
+		}
+	}
+
+	// Render the target version first, so that only variables it adds are
+	// asked for, then the recorded version with the same answers
+	ask := tui.Prompt
+	if updateYes {
+		ask = answers.Defaults
+	}
+	target, err := renderTemplate(project.TemplateURL, updateVersion, project.Answers, ask)
+	if err != nil {
+		return fmt.Errorf("failed to render template version %s: %w", versionLabel(updateVersion), err)
+	}
+	defer os.RemoveAll(target.dir)
+
+	given := map[string]interface{}{}
+	for name, value := range project.Answers {
+		given[name] = value
+	}
+	for name, value := range target.variables {
+		given[name] = value
+	}
+	base, err := renderTemplate(project.TemplateURL, revision, given, answers.Defaults)
+	if err != nil {
+		return fmt.Errorf("failed to render template version %s: %w", versionLabel(project.TemplateVersion), err)
+	}
+	defer os.RemoveAll(base.dir)
+
+	// Apply the template changes to the project
+	fmt.Fprintf(cmd.OutOrStdout(), "Updating from template version %s to %s\n",
+		versionLabel(project.TemplateVersion), versionLabel(updateVersion))
+	result, err := update.Apply(base.dir, target.dir, projectDir, "template "+versionLabel(updateVersion))
+	if err != nil {
+		return err
+	}
+	printUpdateResult(cmd.OutOrStdout(), result)
+
+	// Record the new version and answers
+	s := scaffolder.New(target.dir, projectDir, target.variables, target.config)
+	if err := s.UpdateGenesisConfig(project.TemplateURL, updateVersion, target.commit); err != nil {
+		return fmt.Errorf("failed to update genesis.toml: %w", err)
+	}
+
+	if len(result.Conflicts) > 0 {
+		return fmt.Errorf("%d file(s) have conflicts, resolve them before committing the update", len(result.Conflicts))
+	}
+	return nil
+}
+
+// checkClean returns an error unless dir is in a git worktree without
+// uncommitted changes
+func checkClean(dir string) error {
+	repo, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return fmt.Errorf("project is not in a git repository, pass --allow-dirty to update it anyway")
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to get worktree: %w", err)
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return fmt.Errorf("failed to get worktree status: %w", err)
+	}
+	if !status.IsClean() {
+		return fmt.Errorf("project has uncommitted changes, commit or stash them first or pass --allow-dirty")
+	}
+	return nil
+}
+
+// printUpdateResult lists the files an update touched
+func printUpdateResult(w io.Writer, result *update.Result) {
+	groups := []struct {
+		label string
+		paths []string
+	}{
+		{"updated", result.Updated},
+		{"merged", result.Merged},
+		{"added", result.Added},
+		{"removed", result.Removed},
+		{"kept", result.Kept},
+		{"conflict", result.Conflicts},
+	}
+
+	changed := false
+	for _, group := range groups {
+		for _, path := range group.paths {
+			fmt.Fprintf(w, "  %-9s %s\n", group.label, path)
+			changed = true
+		}
+	}
+	if !changed {
+		fmt.Fprintln(w, "  no changes")
+	}
+	if len(result.Kept) > 0 {
+		fmt.Fprintln(w, "Kept files were removed from the template but modified in the project.")
+	}
+}
+
+// versionLabel describes a template version, where an empty version is the
+// default branch
+func versionLabel(version string) string {
+	if version == "" {
+		return "default branch"
+	}
+	return version
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// commitFiles writes the given files to the Git repository in dir, removes
+// the files listed in remove, commits the result and tags it if tag is not
+// empty
+func commitFiles(t *testing.T, dir string, files map[string]string, remove []string, tag string) {
+	repo, err := git.PlainOpen(dir)
+	require.NoError(t, err)
+	w, err := repo.Worktree()
+	require.NoError(t, err)
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	for _, name := range remove {
+		_, err = w.Remove(name)
+		require.NoError(t, err)
+	}
+	_, err = w.Add(".")
+	require.NoError(t, err)
+
+	author := &object.Signature{Name: "Test Author", Email: "test@example.com", When: time.Now()}
+	commit, err := w.Commit("Update", &git.CommitOptions{Author: author})
+	require.NoError(t, err)
+
+	if tag != "" {
+		_, err = repo.CreateTag(tag, commit, &git.CreateTagOptions{Tagger: author, Message: tag})
+		require.NoError(t, err)
+	}
+}
+
+func TestUpdateCommand(t *testing.T) {
+	templateDir := setupTemplateRepo(t, map[string]string{
+		"template.toml": `version = "1.0"
+
+[vars]
+  name = { prompt = "Name:", default = "demo" }`,
+		"README.md.tmpl": "# {{ .name }}\n\nIntro\n\nUsage\n",
+		"main.go.tmpl":   "package main\n\n// v1\n",
+		"old.txt":        "old\n",
+	})
+	commitFiles(t, templateDir, map[string]string{
+		"template.toml": `version = "1.0"
+
+[vars]
+  name = { prompt = "Name:", default = "demo" }
+  port = { prompt = "Port:", type = "int", default = 8080 }`,
+		"README.md.tmpl": "# {{ .name }}\n\nIntro\n\nUsage on port {{ .port }}\n",
+		"main.go.tmpl":   "package main\n\n// v2\n",
+		"new.txt":        "new\n",
+	}, []string{"old.txt"}, "v2.0.0")
+
+	workDir := t.TempDir()
+	_, err := executeInDir(t, workDir, "new", "shop", "--template", templateDir, "--version", "v1.0.0", "--yes", "--data", "name=shop")
+	require.NoError(t, err)
+	projectDir := filepath.Join(workDir, "shop")
+
+	// Commit the project with local changes
+	_, err = git.PlainInit(projectDir, false)
+	require.NoError(t, err)
+	commitFiles(t, projectDir, map[string]string{
+		"README.md": "# shop\n\nOur intro\n\nUsage\n",
+		"main.go":   "package main\n\n// ours\n",
+	}, nil, "")
+
+	// A dirty worktree is refused
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "scratch.txt"), []byte("wip\n"), 0644))
+	_, err = executeInDir(t, projectDir, "update", "--version", "v2.0.0", "--yes")
+	assert.ErrorContains(t, err, "uncommitted changes")
+	require.NoError(t, os.Remove(filepath.Join(projectDir, "scratch.txt")))
+
+	output, err := executeInDir(t, projectDir, "update", "--version", "v2.0.0", "--yes")
+	assert.ErrorContains(t, err, "1 file(s) have conflicts")
+	assert.Contains(t, output, "merged    README.md")
+	assert.Contains(t, output, "added     new.txt")
+	assert.Contains(t, output, "removed   old.txt")
+	assert.Contains(t, output, "conflict  main.go")
+
+	content, err := os.ReadFile(filepath.Join(projectDir, "README.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# shop\n\nOur intro\n\nUsage on port 8080\n", string(content))
+
+	content, err = os.ReadFile(filepath.Join(projectDir, "main.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "package main\n\n<<<<<<< project\n// ours\n=======\n// v2\n>>>>>>> template v2.0.0\n", string(content))
+
+	_, err = os.Stat(filepath.Join(projectDir, "old.txt"))
+	assert.True(t, os.IsNotExist(err))
+
+	// The new version and answers are recorded
+	content, err = os.ReadFile(filepath.Join(projectDir, "genesis.toml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "template_version = \"v2.0.0\"")
+	assert.Contains(t, string(content), "name = \"shop\"\n  port = 8080\n")
+}
@@ -0,0 +1,119 @@
+// Package answers resolves the values of template variables.
+package answers
+
+import (
+	"fmt"
+
+	"github.com/felipevolpatto/genesis/internal/config"
+	"github.com/felipevolpatto/genesis/internal/render"
+)
+
+// AskFunc obtains the value of a variable, e.g. by prompting the user
+type AskFunc func(name string, v config.Variable) (interface{}, error)
+
+// Defaults is an AskFunc that answers every variable with its default value
+func Defaults(name string, v config.Variable) (interface{}, error) {
+	return v.Default, nil
+}
+
+// SkipFunc is called for a variable that is not asked for because its
+// when condition does not hold
+type SkipFunc func(name string, v config.Variable) error
+
+// Resolve determines the values of the named variables, in the order given.
+// A variable whose when condition does not hold, evaluated against the
+// values resolved before it, takes its default value without being asked.
+// Computed variables are rendered from their value expression and
+// converted to their type; every other variable is obtained from ask.
+func Resolve(vars map[string]config.Variable, names []string, ask AskFunc) (map[string]interface{}, error) {
+	return ResolveSkipping(vars, names, ask, nil)
+}
+
+// ResolveSkipping is like Resolve, but calls skip, unless it is nil, for
+// every variable whose when condition does not hold, as soon as that is
+// known. An error returned by skip stops the resolution.
+func ResolveSkipping(vars map[string]config.Variable, names []string, ask AskFunc, skip SkipFunc) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(names))
+
+	for _, name := range names {
+		v, ok := vars[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown variable %q", name)
+		}
+
+		if v.When != "" {
+			ok, err := render.Condition(name, v.When, values)
+			if err != nil {
+				return nil, fmt.Errorf("failed to evaluate condition of variable %q: %w", name, err)
+			}
+			if !ok {
+				if skip != nil {
+					if err := skip(name, v); err != nil {
+						return nil, err
+					}
+				}
+				values[name] = v.Default
+				continue
+			}
+		}
+
+		if v.Value != "" {
+			value, err := compute(name, v, values)
+			if err != nil {
+				return nil, err
+			}
+			values[name] = value
+			continue
+		}
+
+		value, err := ask(name, v)
+		if err != nil {
+			return nil, err
+		}
+		values[name] = value
+	}
+
+	return values, nil
+}
+
+// compute renders the value expression of a computed variable
+func compute(name string, v config.Variable, values map[string]interface{}) (interface{}, error) {
+	rendered, err := render.Expr(name, v.Value, values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute variable %q: %w", name, err)
+	}
+	value, err := v.Convert(rendered)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value of variable %q: %w", name, err)
+	}
+	return value, nil
+}
+
+// Given returns an AskFunc that answers the variables in given with their
+// given value and asks for all other variables with ask. Given values are
+// converted to the variable's type and validated like typed answers, so
+// they may be strings as passed on the command line. It is an error to give
+// a value for an unknown or computed variable.
+func Given(vars map[string]config.Variable, given map[string]interface{}, ask AskFunc) (AskFunc, error) {
+	for name := range given {
+		v, ok := vars[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown variable %q", name)
+		}
+		if v.Value != "" {
+			return nil, fmt.Errorf("variable %q is computed and cannot be set", name)
+		}
+	}
+
+	return func(name string, v config.Variable) (interface{}, error) {
+		value, ok := given[name]
+		if !ok {
+			return ask(name, v)
+		}
+		converted, err := v.Validate(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for variable %q: %w", name, err)
+		}
+		return converted, nil
+	}, nil
+}
@@ -0,0 +1,190 @@
+package answers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/felipevolpatto/genesis/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve(t *testing.T) {
+	vars := map[string]config.Variable{
+		"name":     {Prompt: "Name:", Default: "demo"},
+		"database": {Prompt: "Database:", Type: config.TypeChoice, Choices: []string{"none", "postgres"}, Default: "none"},
+		"db_name":  {Prompt: "Database name:", Default: "app", When: `{{ ne .database "none" }}`},
+		"docker":   {Prompt: "Docker?", Type: config.TypeBool, Default: false},
+		"registry": {Prompt: "Registry:", Default: "docker.io", When: "{{ .docker }}"},
+	}
+	names := []string{"name", "database", "db_name", "docker", "registry"}
+
+	tests := []struct {
+		name     string
+		answers  map[string]interface{}
+		expected map[string]interface{}
+		asked    []string
+	}{
+		{
+			name:    "conditions hold",
+			answers: map[string]interface{}{"name": "shop", "database": "postgres", "db_name": "orders", "docker": true, "registry": "ghcr.io"},
+			expected: map[string]interface{}{
+				"name": "shop", "database": "postgres", "db_name": "orders", "docker": true, "registry": "ghcr.io",
+			},
+			asked: []string{"name", "database", "db_name", "docker", "registry"},
+		},
+		{
+			name:    "skipped variables take their default",
+			answers: map[string]interface{}{"name": "shop", "database": "none", "docker": false},
+			expected: map[string]interface{}{
+				"name": "shop", "database": "none", "db_name": "app", "docker": false, "registry": "docker.io",
+			},
+			asked: []string{"name", "database", "docker"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var asked []string
+			values, err := Resolve(vars, names, func(name string, v config.Variable) (interface{}, error) {
+				asked = append(asked, name)
+				return tt.answers[name], nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, values)
+			assert.Equal(t, tt.asked, asked)
+		})
+	}
+}
+
+func TestResolveDefaults(t *testing.T) {
+	vars := map[string]config.Variable{
+		"database": {Type: config.TypeChoice, Choices: []string{"none", "postgres"}, Default: "none"},
+		"db_name":  {Default: "app", When: `{{ ne .database "none" }}`},
+	}
+
+	values, err := Resolve(vars, []string{"database", "db_name"}, Defaults)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"database": "none", "db_name": "app"}, values)
+}
+
+func TestResolveComputed(t *testing.T) {
+	vars := map[string]config.Variable{
+		"name":    {Prompt: "Name:", Default: "demo"},
+		"slug":    {Value: `{{ .name | lower | replace " " "-" }}`},
+		"module":  {Value: "github.com/acme/{{ .slug }}"},
+		"replica": {Type: config.TypeInt, Value: "{{ len .name }}"},
+		"docker":  {Type: config.TypeBool, Value: `{{ ne .slug "demo" }}`, When: "{{ .slug }}"},
+	}
+
+	var asked []string
+	values, err := Resolve(vars, []string{"name", "slug", "module", "replica", "docker"}, func(name string, v config.Variable) (interface{}, error) {
+		asked = append(asked, name)
+		return "My Shop", nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name"}, asked)
+	assert.Equal(t, map[string]interface{}{
+		"name":    "My Shop",
+		"slug":    "my-shop",
+		"module":  "github.com/acme/my-shop",
+		"replica": 7,
+		"docker":  true,
+	}, values)
+}
+
+func TestGiven(t *testing.T) {
+	vars := map[string]config.Variable{
+		"name":    {Prompt: "Name:", Default: "demo"},
+		"version": {Prompt: "Version:", Default: "0.1.0", Regex: `^\d+\.\d+\.\d+$`},
+		"port":    {Prompt: "Port:", Type: config.TypeInt, Default: 8080},
+		"slug":    {Value: "{{ .name }}"},
+	}
+	names := []string{"name", "version", "port", "slug"}
+
+	var asked []string
+	ask, err := Given(vars, map[string]interface{}{"name": "shop", "port": "9090"}, func(name string, v config.Variable) (interface{}, error) {
+		asked = append(asked, name)
+		return v.Default, nil
+	})
+	require.NoError(t, err)
+
+	values, err := Resolve(vars, names, ask)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"version"}, asked)
+	assert.Equal(t, map[string]interface{}{"name": "shop", "version": "0.1.0", "port": 9090, "slug": "shop"}, values)
+
+	// Given values are validated like typed answers
+	ask, err = Given(vars, map[string]interface{}{"version": "latest"}, Defaults)
+	require.NoError(t, err)
+	_, err = Resolve(vars, names, ask)
+	assert.ErrorContains(t, err, `invalid value for variable "version"`)
+
+	_, err = Given(vars, map[string]interface{}{"nmae": "shop"}, Defaults)
+	assert.ErrorContains(t, err, `unknown variable "nmae"`)
+
+	_, err = Given(vars, map[string]interface{}{"slug": "shop"}, Defaults)
+	assert.ErrorContains(t, err, `variable "slug" is computed`)
+}
+
+func TestResolveSkipping(t *testing.T) {
+	vars := map[string]config.Variable{
+		"docker":   {Type: config.TypeBool, Default: false},
+		"registry": {Default: "docker.io", When: "{{ .docker }}"},
+		"name":     {Default: "demo"},
+	}
+	names := []string{"docker", "registry", "name"}
+
+	var skipped []string
+	values, err := ResolveSkipping(vars, names, Defaults, func(name string, v config.Variable) error {
+		skipped = append(skipped, name)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"registry"}, skipped)
+	assert.Equal(t, "docker.io", values["registry"])
+
+	// An error from skip stops before later variables are asked for
+	skipErr := errors.New("unused answer")
+	var asked []string
+	_, err = ResolveSkipping(vars, names, func(name string, v config.Variable) (interface{}, error) {
+		asked = append(asked, name)
+		return v.Default, nil
+	}, func(string, config.Variable) error {
+		return skipErr
+	})
+	assert.ErrorIs(t, err, skipErr)
+	assert.Equal(t, []string{"docker"}, asked)
+}
+
+func TestResolveErrors(t *testing.T) {
+	vars := map[string]config.Variable{
+		"name":    {Default: "demo"},
+		"broken":  {When: "{{ .name"},
+		"failing": {},
+		"port":    {Type: config.TypeInt, Value: "{{ .name }}"},
+		"crash":   {Value: "{{ index .name 9 }}"},
+		"slug":    {Value: "{{ .nmae }}-svc"},
+	}
+
+	_, err := Resolve(vars, []string{"missing"}, Defaults)
+	assert.ErrorContains(t, err, `unknown variable "missing"`)
+
+	_, err = Resolve(vars, []string{"name", "broken"}, Defaults)
+	assert.ErrorContains(t, err, `failed to evaluate condition of variable "broken"`)
+
+	_, err = Resolve(vars, []string{"name", "port"}, Defaults)
+	assert.ErrorContains(t, err, `invalid value of variable "port"`)
+
+	_, err = Resolve(vars, []string{"name", "crash"}, Defaults)
+	assert.ErrorContains(t, err, `failed to compute variable "crash"`)
+
+	_, err = Resolve(vars, []string{"name", "slug"}, Defaults)
+	assert.ErrorContains(t, err, `map has no entry for key "nmae"`)
+
+	askErr := errors.New("interrupted")
+	_, err = Resolve(vars, []string{"failing"}, func(string, config.Variable) (interface{}, error) {
+		return nil, askErr
+	})
+	assert.ErrorIs(t, err, askErr)
+}
@@ -0,0 +1,39 @@
+package answers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadFile reads answers from a TOML, YAML or JSON file, chosen by its
+// extension. The file holds a single table mapping variable names to their
+// values.
+func LoadFile(path string) (map[string]interface{}, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read answers file: %w", err)
+	}
+
+	values := map[string]interface{}{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".toml":
+		err = toml.Unmarshal(content, &values)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &values)
+	case ".json":
+		err = json.Unmarshal(content, &values)
+	default:
+		return nil, fmt.Errorf("unsupported answers file format %q, expected .toml, .yaml or .json", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse answers file: %w", err)
+	}
+
+	return values, nil
+}
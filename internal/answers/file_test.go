@@ -0,0 +1,65 @@
+package answers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/felipevolpatto/genesis/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFile(t *testing.T) {
+	vars := map[string]config.Variable{
+		"name":     {},
+		"port":     {Type: config.TypeInt},
+		"docker":   {Type: config.TypeBool},
+		"features": {Type: config.TypeMultiSelect, Choices: []string{"ci", "docs"}},
+	}
+	expected := map[string]interface{}{"name": "shop", "port": 8080, "docker": true, "features": []string{"ci", "docs"}}
+
+	tests := []struct {
+		file    string
+		content string
+	}{
+		{"answers.toml", "name = \"shop\"\nport = 8080\ndocker = true\nfeatures = [\"ci\", \"docs\"]\n"},
+		{"answers.yaml", "name: shop\nport: 8080\ndocker: true\nfeatures: [ci, docs]\n"},
+		{"answers.yml", "name: shop\nport: 8080\ndocker: true\nfeatures:\n  - ci\n  - docs\n"},
+		{"answers.json", `{"name": "shop", "port": 8080, "docker": true, "features": ["ci", "docs"]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0644))
+
+			given, err := LoadFile(path)
+			require.NoError(t, err)
+
+			// The decoded values convert to the variable types
+			ask, err := Given(vars, given, nil)
+			require.NoError(t, err)
+			values, err := Resolve(vars, []string{"name", "port", "docker", "features"}, ask)
+			require.NoError(t, err)
+			assert.Equal(t, expected, values)
+		})
+	}
+}
+
+func TestLoadFileErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := LoadFile(filepath.Join(dir, "missing.toml"))
+	assert.ErrorContains(t, err, "failed to read answers file")
+
+	path := filepath.Join(dir, "answers.ini")
+	require.NoError(t, os.WriteFile(path, []byte("name=shop"), 0644))
+	_, err = LoadFile(path)
+	assert.ErrorContains(t, err, `unsupported answers file format ".ini"`)
+
+	path = filepath.Join(dir, "answers.json")
+	require.NoError(t, os.WriteFile(path, []byte(`["shop"]`), 0644))
+	_, err = LoadFile(path)
+	assert.ErrorContains(t, err, "failed to parse answers file")
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"text/template/parse"
+
+	"github.com/felipevolpatto/genesis/internal/render"
+)
+
+// references returns the names of the top-level fields, such as .name or
+// $.name, that a template expression refers to. Inside range and with
+// blocks, where dot is rebound, only $.name refers to a top-level field.
+func references(expr string) ([]string, error) {
+	tmpl, err := render.Parse("expr", expr)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	var walk func(node parse.Node, top bool)
+	walk = func(node parse.Node, top bool) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, child := range n.Nodes {
+				walk(child, top)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe, top)
+		case *parse.IfNode:
+			walk(n.Pipe, top)
+			walk(n.List, top)
+			walk(n.ElseList, top)
+		case *parse.RangeNode:
+			walk(n.Pipe, top)
+			walk(n.List, false)
+			walk(n.ElseList, top)
+		case *parse.WithNode:
+			walk(n.Pipe, top)
+			walk(n.List, false)
+			walk(n.ElseList, top)
+		case *parse.TemplateNode:
+			walk(n.Pipe, top)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, cmd := range n.Cmds {
+				walk(cmd, top)
+			}
+		case *parse.CommandNode:
+			for _, arg := range n.Args {
+				walk(arg, top)
+			}
+		case *parse.ChainNode:
+			walk(n.Node, top)
+		case *parse.FieldNode:
+			if top {
+				names = append(names, n.Ident[0])
+			}
+		case *parse.VariableNode:
+			if len(n.Ident) > 1 && n.Ident[0] == "$" {
+				names = append(names, n.Ident[1])
+			}
+		}
+	}
+	walk(tmpl.Tree.Root, true)
+
+	return names, nil
+}
+
+// checkDeclared returns an error for the first of names that is not
+// declared in vars
+func checkDeclared(names []string, vars map[string]Variable) error {
+	for _, name := range names {
+		if _, ok := vars[name]; !ok {
+			return fmt.Errorf("undefined variable %q", name)
+		}
+	}
+	return nil
+}
+
+// dependencies returns the variables that the value and condition of a
+// variable refer to. It is an error to refer to an undeclared variable.
+func dependencies(v Variable, vars map[string]Variable) ([]string, error) {
+	var deps []string
+	for _, expr := range []string{v.When, v.Value} {
+		if expr == "" {
+			continue
+		}
+		names, err := references(expr)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkDeclared(names, vars); err != nil {
+			return nil, err
+		}
+		deps = append(deps, names...)
+	}
+	return deps, nil
+}
+
+// orderVariables orders the given variable names so that every variable
+// follows the variables its value and condition refer to. Apart from that
+// the given order is kept. It returns an error if variables refer to each
+// other in a cycle.
+func orderVariables(vars map[string]Variable, names []string) ([]string, error) {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int, len(names))
+	order := make([]string, 0, len(names))
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return cycleError(path, name)
+		}
+		state[name] = visiting
+
+		deps, err := dependencies(vars[name], vars)
+		if err != nil {
+			return fmt.Errorf("invalid variable %q: %w", name, err)
+		}
+		for _, dep := range deps {
+			if err := visit(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+
+		state[name] = visited
+		order = append(order, name)
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// cycleError describes a cycle of variables that ends where it started
+func cycleError(path []string, name string) error {
+	for i, n := range path {
+		if n == name {
+			cycle := append(append([]string{}, path[i:]...), name)
+			return fmt.Errorf("variable cycle detected: %s", strings.Join(cycle, " -> "))
+		}
+	}
+	return fmt.Errorf("variable cycle detected at variable %q", name)
+}
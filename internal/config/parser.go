@@ -4,15 +4,28 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
+	"github.com/felipevolpatto/genesis/internal/glob"
 )
 
-// Variable represents a template variable with its prompt and default value
+// Variable represents a template variable with its prompt and default value.
+// After parsing, Default holds a value of the Go type matching Type (see
+// Variable.Convert). A variable with a Value expression is never asked for
+// but computed from the other variables. Secret variables are not recorded
+// in the generated project.
 type Variable struct {
 	Prompt  string
-	Default string
+	Default interface{}
 	Regex   string
+	Type    string
+	Choices []string
+	When    string
+	Value   string
+	Secret  bool
 }
 
 // Hooks represents pre and post scaffolding hooks
@@ -21,25 +34,142 @@ type Hooks struct {
 	Post []string
 }
 
+// FileRule includes the template files matching Path only when the When
+// condition holds
+type FileRule struct {
+	Path string
+	When string
+}
+
+// Files controls which template files are part of the generated project
+type Files struct {
+	Exclude   []string
+	Rules     []FileRule
+	SkipEmpty bool `toml:"skip_empty"`
+}
+
 // TemplateConfig represents the configuration for a template
 type TemplateConfig struct {
 	Version string
 	Vars    map[string]Variable
 	Hooks   Hooks
+	Files   Files
+
+	// varOrder holds the variable names in declaration order, adjusted so
+	// that variables follow their dependencies
+	varOrder []string
+}
+
+// VarNames returns the names of the template variables in the order they
+// are declared in template.toml, except that a variable always follows the
+// variables its value or condition refer to. Variables that were not
+// declared in a file follow in alphabetical order.
+func (c *TemplateConfig) VarNames() []string {
+	names := make([]string, 0, len(c.Vars))
+	seen := make(map[string]bool, len(c.Vars))
+	for _, name := range c.varOrder {
+		if _, ok := c.Vars[name]; ok && !seen[name] {
+			names = append(names, name)
+			seen[name] = true
+		}
+	}
+
+	var rest []string
+	for name := range c.Vars {
+		if !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+
+	return append(names, rest...)
 }
 
 // Project represents project-specific configuration
 type Project struct {
-	TemplateURL     string `toml:"template_url"`
-	TemplateVersion string `toml:"template_version"`
+	TemplateURL     string                 `toml:"template_url"`
+	TemplateVersion string                 `toml:"template_version"`
+	TemplateCommit  string                 `toml:"template_commit"`
+	Answers         map[string]interface{} `toml:"answers"`
+}
+
+// Revision returns the template revision the project was generated from:
+// the recorded commit if there is one, otherwise the recorded version
+func (p Project) Revision() string {
+	if p.TemplateCommit != "" {
+		return p.TemplateCommit
+	}
+	return p.TemplateVersion
 }
 
 // Task represents a runnable task
 type Task struct {
 	Description string
-	Cmd        string
-	Env        map[string]string
-	Dir        string
+	Cmd         string
+	Cmds        []Command
+	Env         map[string]string
+	Dir         string
+	Deps        []string
+	Sources     []string
+	Generates   []string
+	Timeout     string
+	Retries     int
+	Backoff     string
+}
+
+// Command is an entry of a task's cmds list. It either holds a shell
+// command or references another task to run in its place.
+type Command struct {
+	Cmd  string
+	Task string
+}
+
+// UnmarshalTOML decodes a command from a plain string or from a table with
+// either a cmd or a task key. Empty commands are rejected.
+func (c *Command) UnmarshalTOML(data interface{}) error {
+	switch v := data.(type) {
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return fmt.Errorf("command must not be empty")
+		}
+		c.Cmd = v
+		return nil
+	case map[string]interface{}:
+		for key, value := range v {
+			s, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("command key %q must be a string", key)
+			}
+			if strings.TrimSpace(s) == "" {
+				return fmt.Errorf("command key %q must not be empty", key)
+			}
+			switch key {
+			case "cmd":
+				c.Cmd = s
+			case "task":
+				c.Task = s
+			default:
+				return fmt.Errorf("unknown command key %q", key)
+			}
+		}
+		if (c.Cmd == "") == (c.Task == "") {
+			return fmt.Errorf("command must set exactly one of cmd or task")
+		}
+		return nil
+	default:
+		return fmt.Errorf("command must be a string or a table, got %T", data)
+	}
+}
+
+// TimeoutDuration returns the parsed task timeout, or zero if none is set
+func (t Task) TimeoutDuration() (time.Duration, error) {
+	return parseDuration(t.Timeout)
+}
+
+// BackoffDuration returns the parsed delay before the first retry, or zero
+// if none is set
+func (t Task) BackoffDuration() (time.Duration, error) {
+	return parseDuration(t.Backoff)
 }
 
 // ProjectConfig represents the configuration for a project
@@ -52,14 +182,43 @@ type ProjectConfig struct {
 // ParseTemplateConfig parses a template.toml file
 func ParseTemplateConfig(path string) (*TemplateConfig, error) {
 	var config TemplateConfig
-	if _, err := toml.DecodeFile(path, &config); err != nil {
+	md, err := toml.DecodeFile(path, &config)
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse template config: %w", err)
 	}
 
+	// Remember the order in which variables are declared
+	for _, key := range md.Keys() {
+		if len(key) == 2 && key[0] == "vars" {
+			config.varOrder = append(config.varOrder, key[1])
+		}
+	}
+
 	if config.Version == "" {
 		return nil, fmt.Errorf("template config must specify a version")
 	}
 
+	for name, v := range config.Vars {
+		v, err := normalizeVariable(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid variable %q: %w", name, err)
+		}
+		config.Vars[name] = v
+	}
+
+	config.varOrder, err = orderVariables(config.Vars, config.VarNames())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateHooks(config.Hooks, config.Vars); err != nil {
+		return nil, fmt.Errorf("invalid hooks section: %w", err)
+	}
+
+	if err := validateFiles(config.Files, config.Vars); err != nil {
+		return nil, fmt.Errorf("invalid files section: %w", err)
+	}
+
 	return &config, nil
 }
 
@@ -74,9 +233,103 @@ func ParseProjectConfig(path string) (*ProjectConfig, error) {
 		return nil, fmt.Errorf("project config must specify a version")
 	}
 
+	for name, task := range config.Tasks {
+		if err := validateTask(task); err != nil {
+			return nil, fmt.Errorf("invalid task %q: %w", name, err)
+		}
+	}
+
 	return &config, nil
 }
 
+// validateHooks checks that hook commands are valid templates that only
+// refer to declared variables
+func validateHooks(hooks Hooks, vars map[string]Variable) error {
+	stages := []struct {
+		name  string
+		hooks []string
+	}{
+		{"pre", hooks.Pre},
+		{"post", hooks.Post},
+	}
+	for _, stage := range stages {
+		for _, hook := range stage.hooks {
+			names, err := references(hook)
+			if err != nil {
+				return fmt.Errorf("invalid template syntax in %s-hook %q: %w", stage.name, hook, err)
+			}
+			if err := checkDeclared(names, vars); err != nil {
+				return fmt.Errorf("invalid %s-hook %q: %w", stage.name, hook, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateFiles checks the patterns of the files section and that rule
+// conditions only refer to declared variables
+func validateFiles(files Files, vars map[string]Variable) error {
+	for _, pattern := range files.Exclude {
+		if err := glob.Validate(pattern); err != nil {
+			return err
+		}
+	}
+	for _, rule := range files.Rules {
+		if rule.Path == "" {
+			return fmt.Errorf("rule must specify a path")
+		}
+		if err := glob.Validate(rule.Path); err != nil {
+			return err
+		}
+		if rule.When == "" {
+			return fmt.Errorf("rule for %q must specify a condition", rule.Path)
+		}
+		names, err := references(rule.When)
+		if err != nil {
+			return fmt.Errorf("invalid template syntax in condition for %s: %w", rule.Path, err)
+		}
+		if err := checkDeclared(names, vars); err != nil {
+			return fmt.Errorf("invalid condition for %s: %w", rule.Path, err)
+		}
+	}
+	return nil
+}
+
+// validateTask checks the settings of a task that cannot be validated by
+// decoding alone
+func validateTask(task Task) error {
+	if task.Cmd != "" && len(task.Cmds) > 0 {
+		return fmt.Errorf("cmd and cmds cannot both be set")
+	}
+	if _, err := task.TimeoutDuration(); err != nil {
+		return fmt.Errorf("invalid timeout: %w", err)
+	}
+	if _, err := task.BackoffDuration(); err != nil {
+		return fmt.Errorf("invalid backoff: %w", err)
+	}
+	if task.Retries < 0 {
+		return fmt.Errorf("retries must not be negative")
+	}
+	return nil
+}
+
+// parseDuration parses a duration string such as "5m". An empty string is
+// a zero duration.
+func parseDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("duration %q must not be negative", s)
+	}
+	return d, nil
+}
+
 // FindProjectConfig searches for a genesis.toml file in the current directory
 // and its parents. Returns the path to the config file if found.
 func FindProjectConfig() (string, error) {
@@ -99,4 +352,4 @@ func FindProjectConfig() (string, error) {
 	}
 
 	return "", fmt.Errorf("no genesis.toml found in current directory or its parents")
-} 
\ No newline at end of file
+}
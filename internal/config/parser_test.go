@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -19,10 +20,16 @@ func TestParseTemplateConfig(t *testing.T) {
 [vars]
   name = { prompt = "Enter name:", default = "test" }
   description = { prompt = "Enter description:", default = "A test project" }
+  docker = { prompt = "Use Docker?", type = "choice", choices = ["yes", "no"], default = "no" }
 
 [hooks]
   pre = ["echo 'pre-hook'"]
-  post = ["echo 'post-hook'"]`
+  post = ["echo 'post-hook'"]
+
+[files]
+  exclude = ["docs/**"]
+  skip_empty = true
+  rules = [{ path = "docker/**", when = "{{ eq .docker \"yes\" }}" }]`
 
 	err := os.WriteFile(filepath.Join(tempDir, "template.toml"), []byte(templateConfig), 0644)
 	require.NoError(t, err)
@@ -33,13 +40,155 @@ func TestParseTemplateConfig(t *testing.T) {
 
 	// Verify the config
 	assert.Equal(t, "1.0", config.Version)
-	assert.Len(t, config.Vars, 2)
+	assert.Len(t, config.Vars, 3)
 	assert.Equal(t, "Enter name:", config.Vars["name"].Prompt)
 	assert.Equal(t, "test", config.Vars["name"].Default)
 	assert.Equal(t, "Enter description:", config.Vars["description"].Prompt)
 	assert.Equal(t, "A test project", config.Vars["description"].Default)
 	assert.Equal(t, []string{"echo 'pre-hook'"}, config.Hooks.Pre)
 	assert.Equal(t, []string{"echo 'post-hook'"}, config.Hooks.Post)
+	assert.Equal(t, []string{"docs/**"}, config.Files.Exclude)
+	assert.True(t, config.Files.SkipEmpty)
+	assert.Equal(t, []FileRule{{Path: "docker/**", When: `{{ eq .docker "yes" }}`}}, config.Files.Rules)
+}
+
+func TestParseTemplateConfigTypedVariables(t *testing.T) {
+	tempDir := t.TempDir()
+
+	templateConfig := `version = "1.0"
+
+[vars]
+  name = { prompt = "Enter name:" }
+  use_docker = { prompt = "Use Docker?", type = "bool", default = true }
+  port = { prompt = "Port:", type = "int", default = 8080 }
+  license = { prompt = "License:", type = "choice", choices = ["MIT", "Apache-2.0"], default = "Apache-2.0" }
+  features = { prompt = "Features:", type = "multiselect", choices = ["ci", "docs", "lint"], default = ["ci", "lint"] }`
+
+	err := os.WriteFile(filepath.Join(tempDir, "template.toml"), []byte(templateConfig), 0644)
+	require.NoError(t, err)
+
+	config, err := ParseTemplateConfig(filepath.Join(tempDir, "template.toml"))
+	require.NoError(t, err)
+
+	assert.Equal(t, TypeString, config.Vars["name"].Kind())
+	assert.Equal(t, "", config.Vars["name"].Default)
+	assert.Equal(t, true, config.Vars["use_docker"].Default)
+	assert.Equal(t, 8080, config.Vars["port"].Default)
+	assert.Equal(t, "Apache-2.0", config.Vars["license"].Default)
+	assert.Equal(t, []string{"MIT", "Apache-2.0"}, config.Vars["license"].Choices)
+	assert.Equal(t, []string{"ci", "lint"}, config.Vars["features"].Default)
+
+	// Variables keep the order in which they are declared
+	assert.Equal(t, []string{"name", "use_docker", "port", "license", "features"}, config.VarNames())
+}
+
+func TestVarNames(t *testing.T) {
+	tempDir := t.TempDir()
+
+	templateConfig := `version = "1.0"
+
+[vars]
+  zeta = { prompt = "Zeta:" }
+  alpha = { prompt = "Alpha:" }
+
+[vars.mid]
+  prompt = "Mid:"
+  default = "x"`
+
+	err := os.WriteFile(filepath.Join(tempDir, "template.toml"), []byte(templateConfig), 0644)
+	require.NoError(t, err)
+
+	config, err := ParseTemplateConfig(filepath.Join(tempDir, "template.toml"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"zeta", "alpha", "mid"}, config.VarNames())
+
+	// Variables added in code follow in alphabetical order
+	config.Vars["beta"] = Variable{Prompt: "Beta:"}
+	config.Vars["aardvark"] = Variable{Prompt: "Aardvark:"}
+	assert.Equal(t, []string{"zeta", "alpha", "mid", "aardvark", "beta"}, config.VarNames())
+}
+
+func TestParseTemplateConfigComputedVariables(t *testing.T) {
+	tempDir := t.TempDir()
+
+	templateConfig := `version = "1.0"
+
+[vars]
+  module = { value = "github.com/{{ .owner }}/{{ .slug }}" }
+  slug = { value = "{{ .name | lower | replace \" \" \"-\" }}" }
+  name = { prompt = "Name:" }
+  owner = { prompt = "Owner:", default = "acme" }
+  docker = { prompt = "Docker?", type = "bool" }
+  registry = { prompt = "Registry:", when = "{{ $.docker }}" }`
+
+	err := os.WriteFile(filepath.Join(tempDir, "template.toml"), []byte(templateConfig), 0644)
+	require.NoError(t, err)
+
+	config, err := ParseTemplateConfig(filepath.Join(tempDir, "template.toml"))
+	require.NoError(t, err)
+	assert.Equal(t, "{{ .name | lower | replace \" \" \"-\" }}", config.Vars["slug"].Value)
+
+	// Variables follow the variables they refer to
+	assert.Equal(t, []string{"owner", "name", "slug", "module", "docker", "registry"}, config.VarNames())
+}
+
+func TestParseTemplateConfigVariableCycle(t *testing.T) {
+	tempDir := t.TempDir()
+
+	templateConfig := `version = "1.0"
+
+[vars]
+  name = { prompt = "Name:" }
+  a = { value = "{{ .name }}{{ .b }}" }
+  b = { value = "{{ if .c }}{{ .c }}{{ end }}" }
+  c = { value = "x", when = "{{ .a }}" }`
+
+	err := os.WriteFile(filepath.Join(tempDir, "template.toml"), []byte(templateConfig), 0644)
+	require.NoError(t, err)
+
+	_, err = ParseTemplateConfig(filepath.Join(tempDir, "template.toml"))
+	assert.ErrorContains(t, err, "variable cycle detected: a -> b -> c -> a")
+}
+
+func TestParseTemplateConfigUndefinedVariable(t *testing.T) {
+	tests := []struct {
+		name          string
+		vars          string
+		expectedError string
+	}{
+		{
+			name:          "condition",
+			vars:          `registry = { prompt = "Registry:", when = "{{ .dokcer }}" }`,
+			expectedError: `invalid variable "registry": undefined variable "dokcer"`,
+		},
+		{
+			name:          "value",
+			vars:          `slug = { value = "{{ $.nmae }}-svc" }`,
+			expectedError: `invalid variable "slug": undefined variable "nmae"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "template.toml")
+			content := "version = \"1.0\"\n\n[vars]\n  docker = { prompt = \"Docker?\", type = \"bool\" }\n  " + tt.vars
+			require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+			_, err := ParseTemplateConfig(path)
+			assert.ErrorContains(t, err, tt.expectedError)
+		})
+	}
+
+	// Inside range and with, dot no longer refers to the variables
+	path := filepath.Join(t.TempDir(), "template.toml")
+	content := `version = "1.0"
+
+[vars]
+  features = { prompt = "Features:", type = "multiselect", choices = ["ci", "docs"] }
+  list = { value = "{{ range .features }}{{ . }} {{ end }}" }`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	_, err := ParseTemplateConfig(path)
+	assert.NoError(t, err)
 }
 
 func TestParseProjectConfig(t *testing.T) {
@@ -55,7 +204,11 @@ func TestParseProjectConfig(t *testing.T) {
 
 [tasks]
   test = { description = "Run tests", cmd = "go test ./..." }
-  build = { description = "Build binary", cmd = "go build" }`
+  build = { description = "Build binary", cmd = "go build" }
+  check = { description = "Run all checks", deps = ["test", "build"] }
+  compile = { description = "Compile", cmd = "go build -o bin/app", sources = ["**/*.go"], generates = ["bin/app"] }
+  integration = { description = "Run integration tests", cmd = "go test -tags integration ./...", timeout = "5m", retries = 2, backoff = "1s" }
+  release = { description = "Release", cmds = ["go generate ./...", { task = "build" }, { cmd = "goreleaser" }] }`
 
 	err := os.WriteFile(filepath.Join(tempDir, "genesis.toml"), []byte(projectConfig), 0644)
 	require.NoError(t, err)
@@ -68,11 +221,29 @@ func TestParseProjectConfig(t *testing.T) {
 	assert.Equal(t, "1.0", config.Version)
 	assert.Equal(t, "https://github.com/example/template", config.Project.TemplateURL)
 	assert.Equal(t, "v1.0.0", config.Project.TemplateVersion)
-	assert.Len(t, config.Tasks, 2)
+	assert.Len(t, config.Tasks, 6)
 	assert.Equal(t, "Run tests", config.Tasks["test"].Description)
 	assert.Equal(t, "go test ./...", config.Tasks["test"].Cmd)
 	assert.Equal(t, "Build binary", config.Tasks["build"].Description)
 	assert.Equal(t, "go build", config.Tasks["build"].Cmd)
+	assert.Equal(t, []string{"test", "build"}, config.Tasks["check"].Deps)
+	assert.Equal(t, []string{"**/*.go"}, config.Tasks["compile"].Sources)
+	assert.Equal(t, []string{"bin/app"}, config.Tasks["compile"].Generates)
+
+	assert.Equal(t, []Command{
+		{Cmd: "go generate ./..."},
+		{Task: "build"},
+		{Cmd: "goreleaser"},
+	}, config.Tasks["release"].Cmds)
+
+	integration := config.Tasks["integration"]
+	assert.Equal(t, 2, integration.Retries)
+	timeout, err := integration.TimeoutDuration()
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, timeout)
+	backoff, err := integration.BackoffDuration()
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, backoff)
 }
 
 func TestFindProjectConfig(t *testing.T) {
@@ -175,6 +346,263 @@ func TestParseConfigErrors(t *testing.T) {
 				assert.Error(t, err)
 			},
 		},
+		{
+			name: "invalid timeout",
+			content: `version = "1.0"
+[tasks]
+  test = { cmd = "go test", timeout = "5 minutes" }`,
+			testFunc: func(t *testing.T, content string) {
+				tempDir := t.TempDir()
+				configPath := filepath.Join(tempDir, "genesis.toml")
+				err := os.WriteFile(configPath, []byte(content), 0644)
+				require.NoError(t, err)
+
+				_, err = ParseProjectConfig(configPath)
+				assert.ErrorContains(t, err, "invalid timeout")
+			},
+		},
+		{
+			name: "negative timeout",
+			content: `version = "1.0"
+[tasks]
+  test = { cmd = "go test", timeout = "-1s" }`,
+			testFunc: func(t *testing.T, content string) {
+				tempDir := t.TempDir()
+				configPath := filepath.Join(tempDir, "genesis.toml")
+				err := os.WriteFile(configPath, []byte(content), 0644)
+				require.NoError(t, err)
+
+				_, err = ParseProjectConfig(configPath)
+				assert.ErrorContains(t, err, "invalid timeout")
+			},
+		},
+		{
+			name: "invalid backoff",
+			content: `version = "1.0"
+[tasks]
+  test = { cmd = "go test", retries = 1, backoff = "soon" }`,
+			testFunc: func(t *testing.T, content string) {
+				tempDir := t.TempDir()
+				configPath := filepath.Join(tempDir, "genesis.toml")
+				err := os.WriteFile(configPath, []byte(content), 0644)
+				require.NoError(t, err)
+
+				_, err = ParseProjectConfig(configPath)
+				assert.ErrorContains(t, err, "invalid backoff")
+			},
+		},
+		{
+			name: "negative retries",
+			content: `version = "1.0"
+[tasks]
+  test = { cmd = "go test", retries = -1 }`,
+			testFunc: func(t *testing.T, content string) {
+				tempDir := t.TempDir()
+				configPath := filepath.Join(tempDir, "genesis.toml")
+				err := os.WriteFile(configPath, []byte(content), 0644)
+				require.NoError(t, err)
+
+				_, err = ParseProjectConfig(configPath)
+				assert.ErrorContains(t, err, "retries must not be negative")
+			},
+		},
+		{
+			name: "cmd and cmds",
+			content: `version = "1.0"
+[tasks]
+  test = { cmd = "go test", cmds = ["go vet"] }`,
+			testFunc: func(t *testing.T, content string) {
+				tempDir := t.TempDir()
+				configPath := filepath.Join(tempDir, "genesis.toml")
+				err := os.WriteFile(configPath, []byte(content), 0644)
+				require.NoError(t, err)
+
+				_, err = ParseProjectConfig(configPath)
+				assert.ErrorContains(t, err, "cmd and cmds cannot both be set")
+			},
+		},
+		{
+			name: "command with cmd and task",
+			content: `version = "1.0"
+[tasks]
+  test = { cmds = [{ cmd = "go vet", task = "lint" }] }`,
+			testFunc: func(t *testing.T, content string) {
+				tempDir := t.TempDir()
+				configPath := filepath.Join(tempDir, "genesis.toml")
+				err := os.WriteFile(configPath, []byte(content), 0644)
+				require.NoError(t, err)
+
+				_, err = ParseProjectConfig(configPath)
+				assert.ErrorContains(t, err, "exactly one of cmd or task")
+			},
+		},
+		{
+			name: "command with unknown key",
+			content: `version = "1.0"
+[tasks]
+  test = { cmds = [{ run = "go vet" }] }`,
+			testFunc: func(t *testing.T, content string) {
+				tempDir := t.TempDir()
+				configPath := filepath.Join(tempDir, "genesis.toml")
+				err := os.WriteFile(configPath, []byte(content), 0644)
+				require.NoError(t, err)
+
+				_, err = ParseProjectConfig(configPath)
+				assert.ErrorContains(t, err, "unknown command key")
+			},
+		},
+		{
+			name: "empty command",
+			content: `version = "1.0"
+[tasks]
+  test = { cmds = [""] }`,
+			testFunc: func(t *testing.T, content string) {
+				tempDir := t.TempDir()
+				configPath := filepath.Join(tempDir, "genesis.toml")
+				err := os.WriteFile(configPath, []byte(content), 0644)
+				require.NoError(t, err)
+
+				_, err = ParseProjectConfig(configPath)
+				assert.ErrorContains(t, err, "command must not be empty")
+			},
+		},
+		{
+			name: "empty command table",
+			content: `version = "1.0"
+[tasks]
+  test = { cmds = [{ cmd = "" }] }`,
+			testFunc: func(t *testing.T, content string) {
+				tempDir := t.TempDir()
+				configPath := filepath.Join(tempDir, "genesis.toml")
+				err := os.WriteFile(configPath, []byte(content), 0644)
+				require.NoError(t, err)
+
+				_, err = ParseProjectConfig(configPath)
+				assert.ErrorContains(t, err, "command key \"cmd\" must not be empty")
+			},
+		},
+		{
+			name: "command of wrong type",
+			content: `version = "1.0"
+[tasks]
+  test = { cmds = [42] }`,
+			testFunc: func(t *testing.T, content string) {
+				tempDir := t.TempDir()
+				configPath := filepath.Join(tempDir, "genesis.toml")
+				err := os.WriteFile(configPath, []byte(content), 0644)
+				require.NoError(t, err)
+
+				_, err = ParseProjectConfig(configPath)
+				assert.ErrorContains(t, err, "command must be a string or a table")
+			},
+		},
+		{
+			name: "invalid exclude pattern",
+			content: `version = "1.0"
+[files]
+  exclude = ["docs/["]`,
+			testFunc: func(t *testing.T, content string) {
+				tempDir := t.TempDir()
+				configPath := filepath.Join(tempDir, "template.toml")
+				err := os.WriteFile(configPath, []byte(content), 0644)
+				require.NoError(t, err)
+
+				_, err = ParseTemplateConfig(configPath)
+				assert.ErrorContains(t, err, "invalid pattern")
+			},
+		},
+		{
+			name: "rule without path",
+			content: `version = "1.0"
+[files]
+  rules = [{ when = "true" }]`,
+			testFunc: func(t *testing.T, content string) {
+				tempDir := t.TempDir()
+				configPath := filepath.Join(tempDir, "template.toml")
+				err := os.WriteFile(configPath, []byte(content), 0644)
+				require.NoError(t, err)
+
+				_, err = ParseTemplateConfig(configPath)
+				assert.ErrorContains(t, err, "rule must specify a path")
+			},
+		},
+		{
+			name: "rule without condition",
+			content: `version = "1.0"
+[files]
+  rules = [{ path = "docker/**" }]`,
+			testFunc: func(t *testing.T, content string) {
+				tempDir := t.TempDir()
+				configPath := filepath.Join(tempDir, "template.toml")
+				err := os.WriteFile(configPath, []byte(content), 0644)
+				require.NoError(t, err)
+
+				_, err = ParseTemplateConfig(configPath)
+				assert.ErrorContains(t, err, "must specify a condition")
+			},
+		},
+		{
+			name: "rule with undefined variable",
+			content: `version = "1.0"
+[vars]
+  docker = { prompt = "Docker?", type = "bool" }
+[files]
+  rules = [{ path = "docker/**", when = "{{ .dokcer }}" }]`,
+			testFunc: func(t *testing.T, content string) {
+				tempDir := t.TempDir()
+				configPath := filepath.Join(tempDir, "template.toml")
+				err := os.WriteFile(configPath, []byte(content), 0644)
+				require.NoError(t, err)
+
+				_, err = ParseTemplateConfig(configPath)
+				assert.ErrorContains(t, err, `invalid condition for docker/**: undefined variable "dokcer"`)
+			},
+		},
+		{
+			name: "unknown variable type",
+			content: `version = "1.0"
+[vars]
+  port = { prompt = "Port:", type = "float" }`,
+			testFunc: func(t *testing.T, content string) {
+				tempDir := t.TempDir()
+				configPath := filepath.Join(tempDir, "template.toml")
+				err := os.WriteFile(configPath, []byte(content), 0644)
+				require.NoError(t, err)
+
+				_, err = ParseTemplateConfig(configPath)
+				assert.ErrorContains(t, err, `invalid variable "port": unknown type "float"`)
+			},
+		},
+		{
+			name: "default of wrong type",
+			content: `version = "1.0"
+[vars]
+  port = { prompt = "Port:", type = "int", default = "eighty" }`,
+			testFunc: func(t *testing.T, content string) {
+				tempDir := t.TempDir()
+				configPath := filepath.Join(tempDir, "template.toml")
+				err := os.WriteFile(configPath, []byte(content), 0644)
+				require.NoError(t, err)
+
+				_, err = ParseTemplateConfig(configPath)
+				assert.ErrorContains(t, err, `invalid default`)
+			},
+		},
+		{
+			name: "default not among choices",
+			content: `version = "1.0"
+[vars]
+  license = { prompt = "License:", type = "choice", choices = ["MIT"], default = "GPL" }`,
+			testFunc: func(t *testing.T, content string) {
+				tempDir := t.TempDir()
+				configPath := filepath.Join(tempDir, "template.toml")
+				err := os.WriteFile(configPath, []byte(content), 0644)
+				require.NoError(t, err)
+
+				_, err = ParseTemplateConfig(configPath)
+				assert.ErrorContains(t, err, `expected one of MIT`)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -182,4 +610,4 @@ func TestParseConfigErrors(t *testing.T) {
 			tt.testFunc(t, tt.content)
 		})
 	}
-} 
\ No newline at end of file
+}
@@ -0,0 +1,214 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/felipevolpatto/genesis/internal/render"
+)
+
+// Variable types
+const (
+	TypeString      = "string"
+	TypeBool        = "bool"
+	TypeInt         = "int"
+	TypeChoice      = "choice"
+	TypeMultiSelect = "multiselect"
+)
+
+// Kind returns the type of the variable, which defaults to string
+func (v Variable) Kind() string {
+	if v.Type == "" {
+		return TypeString
+	}
+	return v.Type
+}
+
+// Convert converts a value to the Go type of the variable: string for
+// string and choice variables, bool, int, and []string for multiselect
+// variables. Values may be given in their TOML, YAML or JSON form or as
+// strings, as typed on the command line; multiselect strings are
+// comma-separated. Choice and multiselect values must be among the choices.
+func (v Variable) Convert(value interface{}) (interface{}, error) {
+	switch v.Kind() {
+	case TypeString:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a string, got %v", value)
+		}
+		return s, nil
+
+	case TypeBool:
+		switch b := value.(type) {
+		case bool:
+			return b, nil
+		case string:
+			parsed, err := strconv.ParseBool(b)
+			if err != nil {
+				return nil, fmt.Errorf("expected a boolean, got %q", b)
+			}
+			return parsed, nil
+		}
+		return nil, fmt.Errorf("expected a boolean, got %v", value)
+
+	case TypeInt:
+		switch n := value.(type) {
+		case int:
+			return n, nil
+		case int64:
+			return int(n), nil
+		case float64:
+			if n != math.Trunc(n) {
+				return nil, fmt.Errorf("expected an integer, got %v", n)
+			}
+			return int(n), nil
+		case string:
+			parsed, err := strconv.Atoi(strings.TrimSpace(n))
+			if err != nil {
+				return nil, fmt.Errorf("expected an integer, got %q", n)
+			}
+			return parsed, nil
+		}
+		return nil, fmt.Errorf("expected an integer, got %v", value)
+
+	case TypeChoice:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected one of %s, got %v", strings.Join(v.Choices, ", "), value)
+		}
+		if !v.hasChoice(s) {
+			return nil, fmt.Errorf("expected one of %s, got %q", strings.Join(v.Choices, ", "), s)
+		}
+		return s, nil
+
+	case TypeMultiSelect:
+		var items []string
+		switch list := value.(type) {
+		case []string:
+			items = list
+		case []interface{}:
+			for _, item := range list {
+				s, ok := item.(string)
+				if !ok {
+					return nil, fmt.Errorf("expected a list of strings, got %v", value)
+				}
+				items = append(items, s)
+			}
+		case string:
+			for _, item := range strings.Split(list, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+		default:
+			return nil, fmt.Errorf("expected a list of strings, got %v", value)
+		}
+
+		selected := make([]string, 0, len(items))
+		for _, item := range items {
+			if !v.hasChoice(item) {
+				return nil, fmt.Errorf("%q is not one of %s", item, strings.Join(v.Choices, ", "))
+			}
+			selected = append(selected, item)
+		}
+		return selected, nil
+	}
+
+	return nil, fmt.Errorf("unknown type %q", v.Type)
+}
+
+// Validate converts a value like Convert and checks string values against
+// the variable's regex, as is done for answers typed at a prompt
+func (v Variable) Validate(value interface{}) (interface{}, error) {
+	converted, err := v.Convert(value)
+	if err != nil {
+		return nil, err
+	}
+	if v.Regex != "" {
+		if s, ok := converted.(string); ok && !regexp.MustCompile(v.Regex).MatchString(s) {
+			return nil, fmt.Errorf("%q does not match %s", s, v.Regex)
+		}
+	}
+	return converted, nil
+}
+
+// zero returns the value a variable has when it specifies no default
+func (v Variable) zero() interface{} {
+	switch v.Kind() {
+	case TypeBool:
+		return false
+	case TypeInt:
+		return 0
+	case TypeChoice:
+		return v.Choices[0]
+	case TypeMultiSelect:
+		return []string{}
+	}
+	return ""
+}
+
+// hasChoice reports whether s is one of the variable's choices
+func (v Variable) hasChoice(s string) bool {
+	for _, choice := range v.Choices {
+		if choice == s {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeVariable validates a variable and converts its default value to
+// the variable's Go type
+func normalizeVariable(v Variable) (Variable, error) {
+	switch v.Kind() {
+	case TypeString, TypeBool, TypeInt:
+		if len(v.Choices) > 0 {
+			return v, fmt.Errorf("choices are only allowed for choice and multiselect variables")
+		}
+	case TypeChoice, TypeMultiSelect:
+		if len(v.Choices) == 0 {
+			return v, fmt.Errorf("%s variables must specify choices", v.Type)
+		}
+	default:
+		return v, fmt.Errorf("unknown type %q", v.Type)
+	}
+
+	if v.Regex != "" {
+		if v.Kind() != TypeString {
+			return v, fmt.Errorf("regex is only allowed for string variables")
+		}
+		if _, err := regexp.Compile(v.Regex); err != nil {
+			return v, fmt.Errorf("invalid regex: %w", err)
+		}
+	}
+
+	if v.When != "" {
+		if _, err := render.Parse("when", v.When); err != nil {
+			return v, fmt.Errorf("invalid condition: %w", err)
+		}
+	}
+
+	if v.Value != "" {
+		if v.Prompt != "" {
+			return v, fmt.Errorf("computed variables cannot have a prompt")
+		}
+		if _, err := render.Parse("value", v.Value); err != nil {
+			return v, fmt.Errorf("invalid value: %w", err)
+		}
+	}
+
+	if v.Default == nil {
+		v.Default = v.zero()
+		return v, nil
+	}
+
+	value, err := v.Convert(v.Default)
+	if err != nil {
+		return v, fmt.Errorf("invalid default: %w", err)
+	}
+	v.Default = value
+	return v, nil
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVariableConvert(t *testing.T) {
+	choices := []string{"docker", "ci", "docs"}
+
+	tests := []struct {
+		name          string
+		variable      Variable
+		value         interface{}
+		expected      interface{}
+		expectedError string
+	}{
+		{name: "string", variable: Variable{}, value: "demo", expected: "demo"},
+		{name: "string from number", variable: Variable{}, value: int64(1), expectedError: "expected a string"},
+		{name: "bool", variable: Variable{Type: TypeBool}, value: true, expected: true},
+		{name: "bool from string", variable: Variable{Type: TypeBool}, value: "false", expected: false},
+		{name: "invalid bool", variable: Variable{Type: TypeBool}, value: "maybe", expectedError: "expected a boolean"},
+		{name: "int", variable: Variable{Type: TypeInt}, value: int64(8080), expected: 8080},
+		{name: "int from string", variable: Variable{Type: TypeInt}, value: " 42", expected: 42},
+		{name: "int from JSON number", variable: Variable{Type: TypeInt}, value: float64(3), expected: 3},
+		{name: "fractional JSON number", variable: Variable{Type: TypeInt}, value: 2.5, expectedError: "expected an integer"},
+		{name: "invalid int", variable: Variable{Type: TypeInt}, value: "4.2", expectedError: "expected an integer"},
+		{name: "choice", variable: Variable{Type: TypeChoice, Choices: choices}, value: "ci", expected: "ci"},
+		{name: "unknown choice", variable: Variable{Type: TypeChoice, Choices: choices}, value: "k8s", expectedError: "expected one of docker, ci, docs"},
+		{
+			name:     "multiselect",
+			variable: Variable{Type: TypeMultiSelect, Choices: choices},
+			value:    []interface{}{"docker", "docs"},
+			expected: []string{"docker", "docs"},
+		},
+		{
+			name:     "multiselect from string",
+			variable: Variable{Type: TypeMultiSelect, Choices: choices},
+			value:    "docker, ci",
+			expected: []string{"docker", "ci"},
+		},
+		{
+			name:     "empty multiselect",
+			variable: Variable{Type: TypeMultiSelect, Choices: choices},
+			value:    "",
+			expected: []string{},
+		},
+		{
+			name:          "unknown multiselect choice",
+			variable:      Variable{Type: TypeMultiSelect, Choices: choices},
+			value:         []string{"docker", "k8s"},
+			expectedError: `"k8s" is not one of docker, ci, docs`,
+		},
+		{name: "unknown type", variable: Variable{Type: "float"}, value: "1.5", expectedError: `unknown type "float"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := tt.variable.Convert(tt.value)
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+func TestVariableValidate(t *testing.T) {
+	v := Variable{Regex: `^\d+\.\d+\.\d+$`}
+
+	value, err := v.Validate("1.2.3")
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3", value)
+
+	_, err = v.Validate("latest")
+	assert.ErrorContains(t, err, `"latest" does not match`)
+
+	_, err = Variable{Type: TypeInt}.Validate("many")
+	assert.ErrorContains(t, err, "expected an integer")
+}
+
+func TestNormalizeVariable(t *testing.T) {
+	tests := []struct {
+		name          string
+		variable      Variable
+		expected      interface{}
+		expectedError string
+	}{
+		{name: "string without default", variable: Variable{}, expected: ""},
+		{name: "bool without default", variable: Variable{Type: TypeBool}, expected: false},
+		{name: "int default", variable: Variable{Type: TypeInt, Default: int64(3)}, expected: 3},
+		{name: "choice without default", variable: Variable{Type: TypeChoice, Choices: []string{"a", "b"}}, expected: "a"},
+		{name: "multiselect without default", variable: Variable{Type: TypeMultiSelect, Choices: []string{"a"}}, expected: []string{}},
+		{name: "choice without choices", variable: Variable{Type: TypeChoice}, expectedError: "must specify choices"},
+		{name: "choices on string", variable: Variable{Choices: []string{"a"}}, expectedError: "choices are only allowed"},
+		{name: "regex on int", variable: Variable{Type: TypeInt, Regex: "^1"}, expectedError: "regex is only allowed"},
+		{name: "invalid regex", variable: Variable{Regex: "("}, expectedError: "invalid regex"},
+		{name: "invalid default", variable: Variable{Type: TypeBool, Default: "maybe"}, expectedError: "invalid default"},
+		{name: "invalid condition", variable: Variable{When: "{{ .database"}, expectedError: "invalid condition"},
+		{name: "computed", variable: Variable{Value: "{{ .name | lower }}"}, expected: ""},
+		{name: "computed with prompt", variable: Variable{Prompt: "Slug:", Value: "{{ .name }}"}, expectedError: "cannot have a prompt"},
+		{name: "invalid value", variable: Variable{Value: "{{ .name | nope }}"}, expectedError: "invalid value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := normalizeVariable(tt.variable)
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, v.Default)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// Package diff compares and merges text files line by line.
+package diff
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// Op is the kind of a line in an edit script
+type Op int
+
+// Edit operations
+const (
+	Equal Op = iota
+	Delete
+	Insert
+)
+
+// Edit is a line of an edit script
+type Edit struct {
+	Op   Op
+	Line string
+}
+
+// IsBinary reports whether content looks like a binary file, which cannot
+// be compared line by line
+func IsBinary(content []byte) bool {
+	return bytes.IndexByte(content, 0) >= 0
+}
+
+// SplitLines splits text into lines, each keeping its line ending
+func SplitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// Lines returns an edit script that turns text a into text b. Lines that
+// are deleted and inserted at the same place are listed with all
+// deletions first.
+func Lines(a, b string) []Edit {
+	dmp := diffmatchpatch.New()
+	dmp.DiffTimeout = 0
+
+	runesA, runesB, lines := dmp.DiffLinesToRunes(a, b)
+	diffs := dmp.DiffMainRunes(runesA, runesB, false)
+
+	var edits []Edit
+	for _, d := range diffs {
+		op := Equal
+		switch d.Type {
+		case diffmatchpatch.DiffDelete:
+			op = Delete
+		case diffmatchpatch.DiffInsert:
+			op = Insert
+		}
+		for _, r := range d.Text {
+			edits = append(edits, Edit{Op: op, Line: lines[r]})
+		}
+	}
+	return edits
+}
+
+// matches returns, for each line of a, the index of the line of b it is
+// matched with in the edit script from a to b, or -1 if it is deleted
+func matches(a, b string) []int {
+	var m []int
+	j := 0
+	for _, e := range Lines(a, b) {
+		switch e.Op {
+		case Equal:
+			m = append(m, j)
+			j++
+		case Delete:
+			m = append(m, -1)
+		case Insert:
+			j++
+		}
+	}
+	return m
+}
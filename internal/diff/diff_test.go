@@ -0,0 +1,32 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitLines(t *testing.T) {
+	assert.Nil(t, SplitLines(""))
+	assert.Equal(t, []string{"a\n", "b\n"}, SplitLines("a\nb\n"))
+	assert.Equal(t, []string{"a\n", "b"}, SplitLines("a\nb"))
+}
+
+func TestLines(t *testing.T) {
+	edits := Lines("a\nb\nc\n", "a\nB\nc\nd\n")
+	assert.Equal(t, []Edit{
+		{Equal, "a\n"},
+		{Delete, "b\n"},
+		{Insert, "B\n"},
+		{Equal, "c\n"},
+		{Insert, "d\n"},
+	}, edits)
+
+	assert.Empty(t, Lines("", ""))
+	assert.Equal(t, []Edit{{Insert, "a\n"}}, Lines("", "a\n"))
+}
+
+func TestIsBinary(t *testing.T) {
+	assert.False(t, IsBinary([]byte("text\n")))
+	assert.True(t, IsBinary([]byte("\x89PNG\x00")))
+}
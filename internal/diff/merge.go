@@ -0,0 +1,81 @@
+package diff
+
+import "strings"
+
+// Merge combines the changes made to base in ours and in theirs. Changes
+// to different lines are both applied. Where ours and theirs change the
+// same lines differently, both versions are kept between conflict markers
+// labelled with oursLabel and theirsLabel, and Merge reports a conflict.
+func Merge(base, ours, theirs, oursLabel, theirsLabel string) (string, bool) {
+	o, a, b := SplitLines(base), SplitLines(ours), SplitLines(theirs)
+	ma, mb := matches(base, ours), matches(base, theirs)
+
+	var out strings.Builder
+	conflict := false
+	i, ia, ib := 0, 0, 0
+	for i < len(o) || ia < len(a) || ib < len(b) {
+		// Copy lines that are unchanged on both sides
+		if i < len(o) && ma[i] == ia && mb[i] == ib {
+			out.WriteString(o[i])
+			i, ia, ib = i+1, ia+1, ib+1
+			continue
+		}
+
+		// Find the next base line that is kept on both sides; everything
+		// before it is a changed chunk
+		end, endA, endB := len(o), len(a), len(b)
+		for k := i; k < len(o); k++ {
+			if ma[k] >= 0 && mb[k] >= 0 {
+				end, endA, endB = k, ma[k], mb[k]
+				break
+			}
+		}
+
+		chunkO, chunkA, chunkB := o[i:end], a[ia:endA], b[ib:endB]
+		switch {
+		case equal(chunkA, chunkO):
+			writeLines(&out, chunkB)
+		case equal(chunkB, chunkO), equal(chunkA, chunkB):
+			writeLines(&out, chunkA)
+		default:
+			conflict = true
+			out.WriteString("<<<<<<< " + oursLabel + "\n")
+			writeLines(&out, terminate(chunkA))
+			out.WriteString("=======\n")
+			writeLines(&out, terminate(chunkB))
+			out.WriteString(">>>>>>> " + theirsLabel + "\n")
+		}
+		i, ia, ib = end, endA, endB
+	}
+
+	return out.String(), conflict
+}
+
+// equal reports whether two lists of lines are the same
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// terminate makes sure the last of the lines ends with a newline, so that
+// a conflict marker can follow it
+func terminate(lines []string) []string {
+	if n := len(lines); n > 0 && !strings.HasSuffix(lines[n-1], "\n") {
+		lines = append(lines[:n-1:n-1], lines[n-1]+"\n")
+	}
+	return lines
+}
+
+// writeLines writes lines to out
+func writeLines(out *strings.Builder, lines []string) {
+	for _, line := range lines {
+		out.WriteString(line)
+	}
+}
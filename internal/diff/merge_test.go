@@ -0,0 +1,85 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		ours     string
+		theirs   string
+		expected string
+		conflict bool
+	}{
+		{
+			name:     "unchanged",
+			base:     "a\nb\n",
+			ours:     "a\nb\n",
+			theirs:   "a\nb\n",
+			expected: "a\nb\n",
+		},
+		{
+			name:     "only theirs changed",
+			base:     "a\nb\nc\n",
+			ours:     "a\nb\nc\n",
+			theirs:   "a\nB\nc\nd\n",
+			expected: "a\nB\nc\nd\n",
+		},
+		{
+			name:     "only ours changed",
+			base:     "a\nb\nc\n",
+			ours:     "x\na\nc\n",
+			theirs:   "a\nb\nc\n",
+			expected: "x\na\nc\n",
+		},
+		{
+			name:     "changes to different lines",
+			base:     "a\nb\nc\nd\ne\n",
+			ours:     "A\nb\nc\nd\ne\n",
+			theirs:   "a\nb\nc\nd\nE\n",
+			expected: "A\nb\nc\nd\nE\n",
+		},
+		{
+			name:     "same change on both sides",
+			base:     "a\nb\nc\n",
+			ours:     "a\nB\nc\n",
+			theirs:   "a\nB\nc\n",
+			expected: "a\nB\nc\n",
+		},
+		{
+			name:     "conflicting changes",
+			base:     "a\nb\nc\n",
+			ours:     "a\nours\nc\n",
+			theirs:   "a\ntheirs\nc\n",
+			expected: "a\n<<<<<<< project\nours\n=======\ntheirs\n>>>>>>> template\nc\n",
+			conflict: true,
+		},
+		{
+			name:     "conflicting insertions",
+			base:     "a\n",
+			ours:     "a\nours",
+			theirs:   "a\ntheirs\n",
+			expected: "a\n<<<<<<< project\nours\n=======\ntheirs\n>>>>>>> template\n",
+			conflict: true,
+		},
+		{
+			name:     "added on both sides without a base",
+			base:     "",
+			ours:     "same\n",
+			theirs:   "same\n",
+			expected: "same\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, conflict := Merge(tt.base, tt.ours, tt.theirs, "project", "template")
+			assert.Equal(t, tt.expected, merged)
+			assert.Equal(t, tt.conflict, conflict)
+		})
+	}
+}
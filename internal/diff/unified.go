@@ -0,0 +1,92 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Unified returns the differences between text a, named fromName, and text
+// b, named toName, in unified diff format with the given number of context
+// lines around each change. It returns an empty string if the texts are
+// equal.
+func Unified(fromName, toName, a, b string, context int) string {
+	edits := Lines(a, b)
+
+	// Positions of the edits in a and b
+	posA := make([]int, len(edits)+1)
+	posB := make([]int, len(edits)+1)
+	var changes []int
+	for i, e := range edits {
+		posA[i+1], posB[i+1] = posA[i], posB[i]
+		if e.Op != Insert {
+			posA[i+1]++
+		}
+		if e.Op != Delete {
+			posB[i+1]++
+		}
+		if e.Op != Equal {
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+
+	for i := 0; i < len(changes); {
+		// Group changes whose contexts touch into a single hunk
+		last := i
+		for last+1 < len(changes) && changes[last+1]-changes[last] <= 2*context+1 {
+			last++
+		}
+		start := max(changes[i]-context, 0)
+		end := min(changes[last]+context+1, len(edits))
+		i = last + 1
+
+		countA, countB := posA[end]-posA[start], posB[end]-posB[start]
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(posA[start], countA), hunkRange(posB[start], countB))
+		for _, e := range edits[start:end] {
+			prefix := " "
+			switch e.Op {
+			case Delete:
+				prefix = "-"
+			case Insert:
+				prefix = "+"
+			}
+			out.WriteString(prefix + e.Line)
+			if !strings.HasSuffix(e.Line, "\n") {
+				out.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+	}
+
+	return out.String()
+}
+
+// hunkRange formats the start line and line count of a hunk side. An empty
+// side starts at the line before it.
+func hunkRange(before, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", before+1)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
+
+// Stat returns the number of lines inserted and deleted to turn text a into
+// text b
+func Stat(a, b string) (insertions, deletions int) {
+	for _, e := range Lines(a, b) {
+		switch e.Op {
+		case Insert:
+			insertions++
+		case Delete:
+			deletions++
+		}
+	}
+	return insertions, deletions
+}
@@ -0,0 +1,57 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnified(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	b := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13"
+
+	expected := `--- a/file
++++ b/file
+@@ -1,5 +1,5 @@
+ 1
+ 2
+-3
++three
+ 4
+ 5
+@@ -11,2 +11,3 @@
+ 11
+ 12
++13
+\ No newline at end of file
+`
+	assert.Equal(t, expected, Unified("a/file", "b/file", a, b, 2))
+
+	// Changes whose contexts touch share a hunk
+	expected = `--- a/file
++++ b/file
+@@ -2,10 +2,10 @@
+ 2
+ 3
+-4
++four
+ 5
+ 6
+ 7
+ 8
+-9
++nine
+ 10
+ 11
+`
+	assert.Equal(t, expected, Unified("a/file", "b/file", a, "1\n2\n3\nfour\n5\n6\n7\n8\nnine\n10\n11\n12\n", 2))
+
+	assert.Equal(t, "--- a/file\n+++ /dev/null\n@@ -1,2 +0,0 @@\n-1\n-2\n", Unified("a/file", "/dev/null", "1\n2\n", "", 3))
+	assert.Empty(t, Unified("a/file", "b/file", a, a, 3))
+}
+
+func TestStat(t *testing.T) {
+	insertions, deletions := Stat("a\nb\nc\n", "a\nB\nc\nd\n")
+	assert.Equal(t, 2, insertions)
+	assert.Equal(t, 1, deletions)
+}
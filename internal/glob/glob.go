@@ -0,0 +1,114 @@
+// Package glob implements path matching with support for "**" segments,
+// which match any number of directories.
+package glob
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Match reports whether a slash-separated path matches the pattern. Pattern
+// segments are matched with path.Match, except for "**" which matches zero
+// or more segments.
+func Match(pattern, name string) bool {
+	return matchSegments(split(pattern), split(name))
+}
+
+// Validate reports whether pattern is well-formed
+func Validate(pattern string) error {
+	if _, err := path.Match(strings.ReplaceAll(pattern, "**", "*"), ""); err != nil {
+		return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	return nil
+}
+
+// Expand walks root and returns the slash-separated paths of all regular
+// files, relative to root, that match at least one of the patterns. The
+// result is sorted and contains no duplicates.
+func Expand(root string, patterns []string) ([]string, error) {
+	for _, pattern := range patterns {
+		if err := Validate(pattern); err != nil {
+			return nil, err
+		}
+	}
+
+	var matches []string
+	err := filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// Skip .git directory
+		if info.IsDir() && info.Name() == ".git" {
+			return filepath.SkipDir
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(root, p)
+		if err != nil {
+			return fmt.Errorf("failed to get relative path: %w", err)
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		for _, pattern := range patterns {
+			if Match(pattern, relPath) {
+				matches = append(matches, relPath)
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// split splits a slash-separated path into its non-empty segments
+func split(p string) []string {
+	var segments []string
+	for _, s := range strings.Split(path.Clean(filepath.ToSlash(p)), "/") {
+		if s != "" && s != "." {
+			segments = append(segments, s)
+		}
+	}
+	return segments
+}
+
+// matchSegments matches path segments against pattern segments
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// Collapse consecutive "**" and try every possible split point
+			for len(pattern) > 0 && pattern[0] == "**" {
+				pattern = pattern[1:]
+			}
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+		pattern = pattern[1:]
+		name = name[1:]
+	}
+
+	return len(name) == 0
+}
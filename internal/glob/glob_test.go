@@ -0,0 +1,71 @@
+package glob
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		name     string
+		expected bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/main.go", false},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "cmd/run/main.go", true},
+		{"cmd/**", "cmd", true},
+		{"cmd/**", "cmd/run/main.go", true},
+		{"cmd/**", "internal/cmd/main.go", false},
+		{"docker/**/*.yml", "docker/compose.yml", true},
+		{"docker/**/*.yml", "docker/dev/compose.yml", true},
+		{"docker/**/*.yml", "docker/dev/compose.yaml", false},
+		{"./src/*.ts", "src/index.ts", true},
+		{"[", "[", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Match(tt.pattern, tt.name))
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate("docker/**/*.yml"))
+	assert.NoError(t, Validate("[a-z]*.go"))
+	assert.Error(t, Validate("docs/["))
+}
+
+func TestExpand(t *testing.T) {
+	root := t.TempDir()
+
+	files := []string{
+		"main.go",
+		"README.md",
+		"cmd/run.go",
+		"cmd/run_test.go",
+		".git/config",
+	}
+	for _, name := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(name), 0644))
+	}
+
+	matches, err := Expand(root, []string{"**/*.go", "cmd/*.go"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cmd/run.go", "cmd/run_test.go", "main.go"}, matches)
+
+	matches, err = Expand(root, []string{"**/config"})
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+
+	_, err = Expand(root, []string{"["})
+	assert.Error(t, err)
+}
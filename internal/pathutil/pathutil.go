@@ -0,0 +1,17 @@
+// Package pathutil provides helpers for working with file system paths.
+package pathutil
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Within reports whether dir is below root. Neither root itself nor a
+// sibling that merely shares its name as a prefix is within it.
+func Within(dir, root string) bool {
+	rel, err := filepath.Rel(root, dir)
+	if err != nil {
+		return false
+	}
+	return rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
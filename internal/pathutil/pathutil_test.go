@@ -0,0 +1,29 @@
+package pathutil
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithin(t *testing.T) {
+	root := filepath.Join("tmp", "project")
+	tests := []struct {
+		dir      string
+		expected bool
+	}{
+		{filepath.Join(root, "src"), true},
+		{filepath.Join(root, "src", "pkg"), true},
+		{root, false},
+		{filepath.Join("tmp"), false},
+		{filepath.Join("tmp", "project-old"), false},
+		{filepath.Join("tmp", "other", "src"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Within(tt.dir, root))
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// Package render renders the template expressions used throughout template
+// configurations, such as file contents, hooks and conditions.
+package render
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/kballard/go-shellquote"
+)
+
+// Funcs are the functions available to every template in addition to the
+// text/template builtins. Functions that transform a string take it as
+// their last argument so they can be used in pipelines, e.g.
+// {{ .name | lower | replace " " "-" }}.
+var Funcs = template.FuncMap{
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"title":      title,
+	"trim":       strings.TrimSpace,
+	"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+	"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+	"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+	"shellquote": shellQuote,
+}
+
+// Parse parses text as a template with the render functions
+func Parse(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(Funcs).Parse(text)
+}
+
+// String renders text as a template with the given data
+func String(name, text string, data interface{}) (string, error) {
+	tmpl, err := Parse(name, text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+	return execute(tmpl, data)
+}
+
+// Expr renders a template expression, such as a hook command, a path
+// segment or a computed value, with the given data. Unlike String,
+// referring to a key that is missing from data is an error rather than
+// rendering as "<no value>".
+func Expr(name, text string, data interface{}) (string, error) {
+	tmpl, err := Parse(name, text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+	return execute(tmpl.Option("missingkey=error"), data)
+}
+
+// execute executes a parsed template with the given data
+func execute(tmpl *template.Template, data interface{}) (string, error) {
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// Condition renders a condition and reports whether it holds. Like Expr,
+// referring to a key that is missing from data is an error.
+func Condition(name, expr string, data interface{}) (bool, error) {
+	result, err := Expr(name, expr, data)
+	if err != nil {
+		return false, err
+	}
+	return IsTrue(result), nil
+}
+
+// IsTrue reports whether the rendered result of a condition holds. Empty
+// output, "false", "0" and "no" are false; anything else is true.
+func IsTrue(result string) bool {
+	switch strings.ToLower(strings.TrimSpace(result)) {
+	case "", "false", "0", "no":
+		return false
+	}
+	return true
+}
+
+// title upper-cases the first letter of every word in s
+func title(s string) string {
+	prev := ' '
+	return strings.Map(func(r rune) rune {
+		isStart := unicode.IsSpace(prev) || prev == '-' || prev == '_'
+		prev = r
+		if isStart {
+			return unicode.ToUpper(r)
+		}
+		return r
+	}, s)
+}
+
+// shellQuote quotes a value as a single shell word, or a list as one word
+// per element, so that answers can be used safely in hook commands
+func shellQuote(value interface{}) string {
+	if list, ok := value.([]string); ok {
+		return shellquote.Join(list...)
+	}
+	return shellquote.Join(fmt.Sprint(value))
+}
@@ -0,0 +1,100 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestString(t *testing.T) {
+	data := map[string]interface{}{"name": "demo", "features": []string{"ci", "docs"}}
+
+	out, err := String("test", "{{ .name }}:{{ range .features }} {{ . }}{{ end }}", data)
+	require.NoError(t, err)
+	assert.Equal(t, "demo: ci docs", out)
+
+	_, err = String("test", "{{ .name", data)
+	assert.ErrorContains(t, err, "failed to parse template")
+
+	_, err = String("test", "{{ index .name 5 }}", data)
+	assert.ErrorContains(t, err, "failed to execute template")
+}
+
+func TestExpr(t *testing.T) {
+	data := map[string]interface{}{"name": "demo"}
+
+	out, err := Expr("test", "echo {{ .name }}", data)
+	require.NoError(t, err)
+	assert.Equal(t, "echo demo", out)
+
+	// Unlike String, a missing key is an error instead of "<no value>"
+	out, err = String("test", "echo {{ .nmae }}", data)
+	require.NoError(t, err)
+	assert.Equal(t, "echo <no value>", out)
+
+	_, err = Expr("test", "echo {{ .nmae }}", data)
+	assert.ErrorContains(t, err, `map has no entry for key "nmae"`)
+}
+
+func TestFuncs(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{`{{ .name | lower | replace " " "-" }}`, "my-project"},
+		{`{{ .name | upper }}`, "MY PROJECT"},
+		{`{{ "my-project_name x" | title }}`, "My-Project_Name X"},
+		{`{{ "  padded " | trim }}`, "padded"},
+		{`{{ .name | trimPrefix "My " }}`, "Project"},
+		{`{{ .name | trimSuffix " Project" }}`, "My"},
+		{`echo {{ .name | shellquote }}`, `echo 'My Project'`},
+		{`echo {{ .evil | shellquote }}`, `echo 'x'\''; rm -rf ~'`},
+		{`echo {{ .list | shellquote }}`, `echo ci 'my docs'`},
+		{`echo {{ .port | shellquote }}`, `echo 8080`},
+	}
+
+	data := map[string]interface{}{
+		"name": "My Project",
+		"evil": "x'; rm -rf ~",
+		"list": []string{"ci", "my docs"},
+		"port": 8080,
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			out, err := String("test", tt.text, data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, out)
+		})
+	}
+}
+
+func TestCondition(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected bool
+	}{
+		{`{{ eq .database "postgres" }}`, true},
+		{`{{ ne .database "postgres" }}`, false},
+		{`{{ .docker }}`, true},
+		{`{{ not .docker }}`, false},
+		{`{{ .port }}`, true},
+		{`{{ if .docker }}yes{{ else }}no{{ end }}`, true},
+		{` 0 `, false},
+		{`FALSE`, false},
+		{``, false},
+	}
+
+	data := map[string]interface{}{"database": "postgres", "docker": true, "port": 8080}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			ok, err := Condition("when", tt.expr, data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, ok)
+		})
+	}
+
+	// A misspelled variable does not render as a true "<no value>"
+	_, err := Condition("when", `{{ .dokcer }}`, data)
+	assert.ErrorContains(t, err, `map has no entry for key "dokcer"`)
+}
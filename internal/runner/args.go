@@ -0,0 +1,127 @@
+package runner
+
+import (
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/felipevolpatto/genesis/internal/config"
+	"github.com/kballard/go-shellquote"
+)
+
+// cliArgsPlaceholder matches the {{.CLI_ARGS}} placeholder in task commands
+var cliArgsPlaceholder = regexp.MustCompile(`\{\{\s*\.CLI_ARGS\s*\}\}`)
+
+// WithArgs sets extra command line arguments that are passed to the tasks
+// named in Run. Dependencies do not receive them.
+func WithArgs(args []string) Option {
+	return func(r *Runner) {
+		r.args = args
+	}
+}
+
+// expandArgs inserts the shell-quoted arguments into cmd in place of the
+// {{.CLI_ARGS}} placeholder. Commands without a placeholder get the
+// arguments appended.
+func expandArgs(cmd string, args []string) string {
+	quoted := quoteArgs(args)
+	if cliArgsPlaceholder.MatchString(cmd) {
+		return cliArgsPlaceholder.ReplaceAllLiteralString(cmd, quoted)
+	}
+	if quoted == "" {
+		return cmd
+	}
+	return strings.TrimRight(cmd, " ") + " " + quoted
+}
+
+// quoteArgs quotes arguments for the shell that runs task commands, as
+// chosen by getShellAndArg
+func quoteArgs(args []string) string {
+	if runtime.GOOS == "windows" {
+		return windowsJoin(args)
+	}
+	return shellquote.Join(args...)
+}
+
+// windowsJoin quotes arguments for cmd.exe and programs that split their
+// command line with the usual Windows rules. Arguments containing spaces,
+// quotes or cmd.exe operators are wrapped in double quotes; inside them,
+// quotes and the backslashes preceding them are escaped with backslashes.
+func windowsJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg != "" && !strings.ContainsAny(arg, " \t\"&|<>^()") {
+			quoted[i] = arg
+			continue
+		}
+
+		var b strings.Builder
+		b.WriteByte('"')
+		slashes := 0
+		for j := 0; j < len(arg); j++ {
+			c := arg[j]
+			switch c {
+			case '\\':
+				slashes++
+			case '"':
+				b.WriteString(strings.Repeat(`\`, slashes+1))
+				slashes = 0
+			default:
+				slashes = 0
+			}
+			b.WriteByte(c)
+		}
+		b.WriteString(strings.Repeat(`\`, slashes))
+		b.WriteByte('"')
+		quoted[i] = b.String()
+	}
+	return strings.Join(quoted, " ")
+}
+
+// resolveArgs returns a copy of tasks in which the requested tasks receive
+// the Runner's extra arguments and every other placeholder is removed
+func (r *Runner) resolveArgs(tasks map[string]config.Task, names []string) map[string]config.Task {
+	requested := make(map[string]bool, len(names))
+	for _, name := range names {
+		requested[name] = true
+	}
+
+	resolved := make(map[string]config.Task, len(tasks))
+	for name, task := range tasks {
+		if requested[name] {
+			resolved[name] = expandTaskArgs(task, r.args)
+		} else {
+			resolved[name] = expandTaskArgs(task, nil)
+		}
+	}
+	return resolved
+}
+
+// expandTaskArgs inserts the arguments into the commands of a task. In a
+// cmds list every placeholder is replaced; without any placeholder the
+// arguments are appended to the last shell command.
+func expandTaskArgs(task config.Task, args []string) config.Task {
+	if len(task.Cmds) == 0 {
+		task.Cmd = expandArgs(task.Cmd, args)
+		return task
+	}
+
+	last := -1
+	placeholder := false
+	for i, c := range task.Cmds {
+		if c.Task == "" {
+			last = i
+			placeholder = placeholder || cliArgsPlaceholder.MatchString(c.Cmd)
+		}
+	}
+
+	cmds := make([]config.Command, len(task.Cmds))
+	for i, c := range task.Cmds {
+		if cliArgsPlaceholder.MatchString(c.Cmd) || (!placeholder && i == last) {
+			c.Cmd = expandArgs(c.Cmd, args)
+		}
+		cmds[i] = c
+	}
+	task.Cmds = cmds
+	return task
+}
@@ -0,0 +1,128 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/felipevolpatto/genesis/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		cmd      string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "no args",
+			cmd:      "go test ./...",
+			expected: "go test ./...",
+		},
+		{
+			name:     "appended",
+			cmd:      "go test ./...",
+			args:     []string{"-run", "TestFoo", "-v"},
+			expected: "go test ./... -run TestFoo -v",
+		},
+		{
+			name:     "placeholder",
+			cmd:      "go test {{.CLI_ARGS}} ./...",
+			args:     []string{"-v"},
+			expected: "go test -v ./...",
+		},
+		{
+			name:     "placeholder with spaces",
+			cmd:      "go test {{ .CLI_ARGS }} ./...",
+			args:     []string{"-v"},
+			expected: "go test -v ./...",
+		},
+		{
+			name:     "placeholder without args",
+			cmd:      "go test {{.CLI_ARGS}}",
+			expected: "go test ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, expandArgs(tt.cmd, tt.args))
+		})
+	}
+}
+
+func TestQuoteArgs(t *testing.T) {
+	// Arguments are quoted for the shell that runs task commands
+	args := []string{"hello world", "it's", "$HOME"}
+	if runtime.GOOS == "windows" {
+		assert.Equal(t, `"hello world" it's $HOME`, quoteArgs(args))
+	} else {
+		assert.Equal(t, `'hello world' it\'s \$HOME`, quoteArgs(args))
+	}
+}
+
+func TestWindowsJoin(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"-run", "Test Foo"}, `-run "Test Foo"`},
+		{[]string{""}, `""`},
+		{[]string{`say "hi"`}, `"say \"hi\""`},
+		{[]string{`C:\my dir\`}, `"C:\my dir\\"`},
+		{[]string{`a\"b`}, `"a\\\"b"`},
+		{[]string{"a&b", "x|y"}, `"a&b" "x|y"`},
+		{[]string{`C:\dir\file`}, `C:\dir\file`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, windowsJoin(tt.args))
+		})
+	}
+}
+
+func TestRunWithArgs(t *testing.T) {
+	tasks := map[string]config.Task{
+		"setup": {Cmd: "echo setup {{.CLI_ARGS}}"},
+		"greet": {Cmd: "echo hello", Deps: []string{"setup"}},
+	}
+
+	stdout := new(bytes.Buffer)
+	r := New(WithArgs([]string{"big world"}), WithOutput(stdout, new(bytes.Buffer)))
+	err := r.Run(context.Background(), tasks, "greet")
+	require.NoError(t, err)
+
+	// Only the requested task receives the arguments
+	assert.Equal(t, "setup\nhello big world\n", stdout.String())
+}
+
+func TestExpandTaskArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		cmds     []config.Command
+		expected []config.Command
+	}{
+		{
+			name:     "appended to last command",
+			cmds:     []config.Command{{Cmd: "go vet ./..."}, {Cmd: "go test ./..."}, {Task: "lint"}},
+			expected: []config.Command{{Cmd: "go vet ./..."}, {Cmd: "go test ./... -v"}, {Task: "lint"}},
+		},
+		{
+			name:     "placeholders",
+			cmds:     []config.Command{{Cmd: "go vet {{.CLI_ARGS}}"}, {Cmd: "go test {{.CLI_ARGS}}"}, {Cmd: "echo done"}},
+			expected: []config.Command{{Cmd: "go vet -v"}, {Cmd: "go test -v"}, {Cmd: "echo done"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := config.Task{Cmds: tt.cmds}
+			assert.Equal(t, tt.expected, expandTaskArgs(task, []string{"-v"}).Cmds)
+		})
+	}
+}
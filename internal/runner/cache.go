@@ -0,0 +1,152 @@
+package runner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/felipevolpatto/genesis/internal/config"
+	"github.com/felipevolpatto/genesis/internal/glob"
+)
+
+// unsafeFileChars matches characters that are not allowed in checksum file names
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// cacheable reports whether a task declares sources and can be skipped
+// when they have not changed
+func cacheable(task config.Task) bool {
+	return len(task.Sources) > 0
+}
+
+// fingerprint computes a checksum over the task commands, its environment
+// and the content of every file matched by its sources in dir
+func fingerprint(dir string, task config.Task) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "cmd:%s\n", task.Cmd)
+	for _, c := range task.Cmds {
+		if c.Task != "" {
+			fmt.Fprintf(h, "task:%s\n", c.Task)
+		} else {
+			fmt.Fprintf(h, "cmd:%s\n", c.Cmd)
+		}
+	}
+
+	keys := make([]string, 0, len(task.Env))
+	for k := range task.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(h, "env:%s=%s\n", k, task.Env[k])
+	}
+
+	files, err := glob.Expand(dir, task.Sources)
+	if err != nil {
+		return "", fmt.Errorf("failed to expand sources: %w", err)
+	}
+
+	for _, name := range files {
+		f, err := os.Open(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			return "", fmt.Errorf("failed to read source %s: %w", name, err)
+		}
+
+		fh := sha256.New()
+		_, err = io.Copy(fh, f)
+		f.Close()
+		if err != nil {
+			return "", fmt.Errorf("failed to read source %s: %w", name, err)
+		}
+
+		fmt.Fprintf(h, "file:%s:%x\n", name, fh.Sum(nil))
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// generatesExist reports whether every pattern in the task's generates list
+// matches at least one file in dir
+func generatesExist(dir string, task config.Task) (bool, error) {
+	for _, pattern := range task.Generates {
+		files, err := glob.Expand(dir, []string{pattern})
+		if err != nil {
+			return false, fmt.Errorf("failed to expand generates: %w", err)
+		}
+		if len(files) == 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// checksumPath returns the path of the stored checksum for a task
+func (r *Runner) checksumPath(name string) string {
+	return filepath.Join(r.cacheDir, "checksums", unsafeFileChars.ReplaceAllString(name, "_"))
+}
+
+// checksum returns the current fingerprint of a task, or an empty string
+// when the task is not cached
+func (r *Runner) checksum(task config.Task) (string, error) {
+	if r.cacheDir == "" || !cacheable(task) {
+		return "", nil
+	}
+	return fingerprint(r.taskDir(task), task)
+}
+
+// upToDate reports whether the stored checksum of a task matches sum and all
+// of its generated files are present
+func (r *Runner) upToDate(name string, task config.Task, sum string) (bool, error) {
+	if sum == "" || r.force {
+		return false, nil
+	}
+
+	stored, err := os.ReadFile(r.checksumPath(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read checksum: %w", err)
+	}
+	if strings.TrimSpace(string(stored)) != sum {
+		return false, nil
+	}
+
+	return generatesExist(r.taskDir(task), task)
+}
+
+// storeChecksum records sum as the checksum of a task
+func (r *Runner) storeChecksum(name string, sum string) error {
+	if sum == "" {
+		return nil
+	}
+
+	path := r.checksumPath(name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create checksum directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, []byte(sum+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write checksum: %w", err)
+	}
+
+	return nil
+}
+
+// taskDir returns the working directory of a task, resolving a relative
+// task dir against the Runner's directory
+func (r *Runner) taskDir(task config.Task) string {
+	if filepath.IsAbs(task.Dir) {
+		return task.Dir
+	}
+	dir := filepath.Join(r.dir, task.Dir)
+	if dir == "" {
+		return "."
+	}
+	return dir
+}
@@ -0,0 +1,147 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/felipevolpatto/genesis/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunSkipsUpToDateTasks(t *testing.T) {
+	// Create a project with a source file
+	projectDir := t.TempDir()
+	cacheDir := filepath.Join(projectDir, ".genesis")
+	logFile := filepath.Join(projectDir, "log.txt")
+
+	err := os.WriteFile(filepath.Join(projectDir, "main.go"), []byte("package main"), 0644)
+	require.NoError(t, err)
+
+	tasks := map[string]config.Task{
+		"build": {
+			Cmd:       "echo build >> " + logFile + " && touch app",
+			Dir:       projectDir,
+			Sources:   []string{"**/*.go"},
+			Generates: []string{"app"},
+		},
+	}
+
+	run := func(opts ...Option) string {
+		stderr := new(bytes.Buffer)
+		opts = append(opts, WithCacheDir(cacheDir), WithOutput(new(bytes.Buffer), stderr))
+		require.NoError(t, New(opts...).Run(context.Background(), tasks, "build"))
+		return stderr.String()
+	}
+	runs := func() int {
+		content, err := os.ReadFile(logFile)
+		require.NoError(t, err)
+		return bytes.Count(content, []byte("build"))
+	}
+
+	// The first run executes the task and stores its checksum
+	run()
+	assert.Equal(t, 1, runs())
+	_, err = os.Stat(filepath.Join(cacheDir, "checksums", "build"))
+	assert.NoError(t, err)
+
+	// Nothing changed, so the task is skipped
+	output := run()
+	assert.Contains(t, output, "task build is up to date")
+	assert.Equal(t, 1, runs())
+
+	// --force overrides the checksum
+	run(WithForce(true))
+	assert.Equal(t, 2, runs())
+
+	// Changing a source invalidates the checksum
+	err = os.WriteFile(filepath.Join(projectDir, "main.go"), []byte("package main\n\nfunc main() {}"), 0644)
+	require.NoError(t, err)
+	run()
+	assert.Equal(t, 3, runs())
+
+	// Removing a generated file invalidates the checksum
+	require.NoError(t, os.Remove(filepath.Join(projectDir, "app")))
+	run()
+	assert.Equal(t, 4, runs())
+
+	// Changing the environment invalidates the checksum
+	task := tasks["build"]
+	task.Env = map[string]string{"GOOS": "linux"}
+	tasks["build"] = task
+	run()
+	assert.Equal(t, 5, runs())
+	run()
+	assert.Equal(t, 5, runs())
+
+	// The checksum is taken before the run, so a source changed while the
+	// task runs makes the next run execute it again
+	task.Cmd = "echo build >> " + logFile + " && echo '// edited' >> main.go"
+	tasks["build"] = task
+	run()
+	assert.Equal(t, 6, runs())
+	run()
+	assert.Equal(t, 7, runs())
+}
+
+func TestRunResolvesTaskDir(t *testing.T) {
+	// Relative task dirs, sources and generates resolve against the
+	// Runner's directory rather than the current one
+	projectDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(projectDir, "src"), 0755))
+	err := os.WriteFile(filepath.Join(projectDir, "src", "main.go"), []byte("package main"), 0644)
+	require.NoError(t, err)
+
+	tasks := map[string]config.Task{
+		"build": {
+			Cmd:       "echo build >> ../log.txt && touch app",
+			Dir:       "src",
+			Sources:   []string{"*.go"},
+			Generates: []string{"app"},
+		},
+	}
+
+	run := func() string {
+		stderr := new(bytes.Buffer)
+		r := New(WithDir(projectDir), WithCacheDir(filepath.Join(projectDir, ".genesis")), WithOutput(new(bytes.Buffer), stderr))
+		require.NoError(t, r.Run(context.Background(), tasks, "build"))
+		return stderr.String()
+	}
+
+	run()
+	assert.FileExists(t, filepath.Join(projectDir, "src", "app"))
+	assert.Contains(t, run(), "task build is up to date")
+
+	content, err := os.ReadFile(filepath.Join(projectDir, "log.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "build\n", string(content))
+}
+
+func TestFingerprint(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644)
+	require.NoError(t, err)
+
+	task := config.Task{Cmd: "cat a.txt", Sources: []string{"*.txt"}}
+	sum, err := fingerprint(dir, task)
+	require.NoError(t, err)
+
+	// Same inputs give the same fingerprint
+	again, err := fingerprint(dir, task)
+	require.NoError(t, err)
+	assert.Equal(t, sum, again)
+
+	// A different command gives a different fingerprint
+	task.Cmd = "cat a.txt b.txt"
+	changed, err := fingerprint(dir, task)
+	require.NoError(t, err)
+	assert.NotEqual(t, sum, changed)
+
+	// Invalid patterns are reported
+	task.Sources = []string{"["}
+	_, err = fingerprint(dir, task)
+	assert.Error(t, err)
+}
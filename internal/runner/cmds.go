@@ -0,0 +1,54 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/felipevolpatto/genesis/internal/config"
+)
+
+// execCommands executes the named task. A task with a cmds list runs each
+// entry in turn and stops at the first failure; entries that reference
+// another task run that task, after its own dependencies. Timeouts and
+// retries apply to each shell command separately.
+func (r *Runner) execCommands(ctx context.Context, tasks map[string]config.Task, name string, stdout, stderr io.Writer) error {
+	task := tasks[name]
+	if len(task.Cmds) == 0 {
+		return r.execWithRetries(ctx, name, task, stdout, stderr)
+	}
+
+	for _, c := range task.Cmds {
+		if c.Task != "" {
+			if err := r.runReference(ctx, tasks, c.Task, stdout, stderr); err != nil {
+				return err
+			}
+			continue
+		}
+
+		step := task
+		step.Cmd = c.Cmd
+		step.Cmds = nil
+		if err := r.execWithRetries(ctx, name, step, stdout, stderr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runReference runs a task referenced from a cmds list together with its
+// dependencies. Referenced tasks run every time they are referenced, but
+// are still skipped when they are up to date.
+func (r *Runner) runReference(ctx context.Context, tasks map[string]config.Task, name string, stdout, stderr io.Writer) error {
+	order, err := Plan(tasks, []string{name})
+	if err != nil {
+		return err
+	}
+
+	for _, n := range order {
+		if _, err := r.runNamed(ctx, tasks, n, stdout, stderr); err != nil {
+			return fmt.Errorf("failed to run task %q: %w", n, err)
+		}
+	}
+	return nil
+}
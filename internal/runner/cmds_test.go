@@ -0,0 +1,84 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/felipevolpatto/genesis/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunCmds(t *testing.T) {
+	tasks := map[string]config.Task{
+		"generate": {Cmd: "echo generate"},
+		"lint":     {Cmd: "echo lint", Deps: []string{"generate"}},
+		"build": {Cmds: []config.Command{
+			{Cmd: "echo first"},
+			{Task: "lint"},
+			{Cmd: "echo second"},
+		}},
+	}
+
+	stdout := new(bytes.Buffer)
+	r := New(WithOutput(stdout, new(bytes.Buffer)))
+	err := r.Run(context.Background(), tasks, "build")
+	require.NoError(t, err)
+
+	// Referenced tasks run in place, after their own dependencies
+	assert.Equal(t, "first\ngenerate\nlint\nsecond\n", stdout.String())
+}
+
+func TestRunCmdsStopsAtFirstFailure(t *testing.T) {
+	tasks := map[string]config.Task{
+		"broken": {Cmd: "exit 4"},
+		"shell": {Cmds: []config.Command{
+			{Cmd: "echo first"},
+			{Cmd: "exit 3"},
+			{Cmd: "echo second"},
+		}},
+		"reference": {Cmds: []config.Command{
+			{Task: "broken"},
+			{Cmd: "echo second"},
+		}},
+	}
+
+	tests := []struct {
+		name         string
+		task         string
+		expectedOut  string
+		expectedCode int
+		expectedErr  string
+	}{
+		{
+			name:         "shell command",
+			task:         "shell",
+			expectedOut:  "first\n",
+			expectedCode: 3,
+			expectedErr:  `failed to run task "shell"`,
+		},
+		{
+			name:         "task reference",
+			task:         "reference",
+			expectedCode: 4,
+			expectedErr:  `failed to run task "reference": failed to run task "broken"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout := new(bytes.Buffer)
+			r := New(WithOutput(stdout, new(bytes.Buffer)))
+			err := r.Run(context.Background(), tasks, tt.task)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+			assert.Equal(t, tt.expectedOut, stdout.String())
+
+			var exitErr *ExitError
+			require.True(t, errors.As(err, &exitErr))
+			assert.Equal(t, tt.expectedCode, exitErr.Code)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// ExitError reports that a task's process exited with a non-zero status or
+// was terminated by a signal. Code follows the shell convention of 128 plus
+// the signal number for processes killed by a signal.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+// Error returns the message of the underlying error
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// wrapExitError converts an *exec.ExitError into an *ExitError carrying the
+// process exit code. Other errors are returned unchanged.
+func wrapExitError(err error) error {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return err
+	}
+
+	code := exitErr.ExitCode()
+	if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		code = 128 + int(status.Signal())
+	}
+	if code <= 0 {
+		code = 1
+	}
+
+	return &ExitError{Code: code, Err: err}
+}
+
+// TimeoutError reports that a task was stopped because it ran longer than
+// its timeout
+type TimeoutError struct {
+	Timeout time.Duration
+	Err     error
+}
+
+// Error describes the timeout and how the task ended
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("timed out after %s: %v", e.Timeout, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
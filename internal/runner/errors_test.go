@@ -0,0 +1,57 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/felipevolpatto/genesis/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExitError(t *testing.T) {
+	tests := []struct {
+		name         string
+		cmd          string
+		expectedCode int
+	}{
+		{name: "exit status", cmd: "exit 3", expectedCode: 3},
+		{name: "interrupted", cmd: "kill -INT $$", expectedCode: 130},
+		{name: "terminated", cmd: "kill -TERM $$", expectedCode: 143},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New()
+			err := r.RunTask(context.Background(), config.Task{Cmd: tt.cmd})
+			require.Error(t, err)
+
+			var exitErr *ExitError
+			require.True(t, errors.As(err, &exitErr))
+			assert.Equal(t, tt.expectedCode, exitErr.Code)
+		})
+	}
+}
+
+func TestExitErrorThroughRun(t *testing.T) {
+	tasks := map[string]config.Task{
+		"test": {Cmd: "exit 42"},
+	}
+
+	err := New().Run(context.Background(), tasks, "test")
+	require.Error(t, err)
+	assert.Equal(t, `failed to run task "test": exit status 42`, err.Error())
+
+	var exitErr *ExitError
+	require.True(t, errors.As(err, &exitErr))
+	assert.Equal(t, 42, exitErr.Code)
+}
+
+func TestWrapExitErrorPassesOtherErrors(t *testing.T) {
+	assert.NoError(t, wrapExitError(nil))
+
+	err := fmt.Errorf("not an exit error")
+	assert.Equal(t, err, wrapExitError(err))
+}
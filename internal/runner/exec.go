@@ -1,66 +1,189 @@
 package runner
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"runtime"
+	"sync"
+	"time"
 
 	"github.com/felipevolpatto/genesis/internal/config"
 )
 
+// DefaultGracePeriod is how long a cancelled task may take to exit before it
+// is killed
+const DefaultGracePeriod = 5 * time.Second
+
+// waitDelay bounds how long a task's output may stay open after it exited
+const waitDelay = time.Second
+
 // Runner executes tasks defined in genesis.toml
 type Runner struct {
-	shell string
-	arg   string
+	shell     string
+	arg       string
+	stdout    io.Writer
+	stderr    io.Writer
+	jobs      int
+	color     bool
+	dir       string
+	cacheDir  string
+	force     bool
+	debounce  time.Duration
+	grace     time.Duration
+	args      []string
+	keepGoing bool
+	mu        sync.Mutex
+}
+
+// Option configures a Runner
+type Option func(*Runner)
+
+// WithJobs sets the maximum number of tasks that may run concurrently.
+// Values lower than one are treated as one.
+func WithJobs(jobs int) Option {
+	return func(r *Runner) {
+		if jobs < 1 {
+			jobs = 1
+		}
+		r.jobs = jobs
+	}
+}
+
+// WithOutput sets the writers that receive the output of tasks
+func WithOutput(stdout, stderr io.Writer) Option {
+	return func(r *Runner) {
+		r.stdout = stdout
+		r.stderr = stderr
+	}
+}
+
+// WithColor enables colored task name prefixes when tasks run in parallel
+func WithColor(color bool) Option {
+	return func(r *Runner) {
+		r.color = color
+	}
+}
+
+// WithDir sets the directory that task directories, sources and generated
+// files are relative to, normally the directory containing genesis.toml.
+// Tasks without a dir run in it. An empty directory means the current one.
+func WithDir(dir string) Option {
+	return func(r *Runner) {
+		r.dir = dir
+	}
+}
+
+// WithCacheDir sets the directory where task checksums are stored. Tasks
+// that declare sources are skipped when their checksum is unchanged. An
+// empty directory disables caching.
+func WithCacheDir(dir string) Option {
+	return func(r *Runner) {
+		r.cacheDir = dir
+	}
+}
+
+// WithForce makes the Runner execute tasks even when they are up to date
+func WithForce(force bool) Option {
+	return func(r *Runner) {
+		r.force = force
+	}
+}
+
+// WithGracePeriod sets how long a cancelled task may take to exit after
+// being signalled before its process group is killed
+func WithGracePeriod(d time.Duration) Option {
+	return func(r *Runner) {
+		r.grace = d
+	}
+}
+
+// WithContinueOnError makes the Runner keep running the remaining tasks
+// after a failure and print a summary of all results at the end
+func WithContinueOnError(keepGoing bool) Option {
+	return func(r *Runner) {
+		r.keepGoing = keepGoing
+	}
 }
 
 // New creates a new Runner
-func New() *Runner {
+func New(opts ...Option) *Runner {
 	shell, arg := getShellAndArg()
-	return &Runner{
-		shell: shell,
-		arg:   arg,
+	r := &Runner{
+		shell:    shell,
+		arg:      arg,
+		stdout:   os.Stdout,
+		stderr:   os.Stderr,
+		jobs:     1,
+		debounce: DefaultDebounce,
+		grace:    DefaultGracePeriod,
 	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// RunTask executes a task. If ctx is cancelled before the task exits, its
+// process group is stopped as described for Run.
+func (r *Runner) RunTask(ctx context.Context, task config.Task) error {
+	return r.execTask(ctx, task, r.stdout, r.stderr)
 }
 
-// RunTask executes a task
-func (r *Runner) RunTask(task config.Task) error {
+// execTask executes a task in its own process group, writing its output to
+// the given writers. When ctx is cancelled the group receives the signal
+// recorded in the context, or SIGTERM, and is killed if it is still running
+// after the grace period.
+func (r *Runner) execTask(ctx context.Context, task config.Task, stdout, stderr io.Writer) error {
 	// Set up command environment
 	env := os.Environ()
 	for k, v := range task.Env {
 		env = append(env, fmt.Sprintf("%s=%s", k, v))
 	}
 
-	// Set up working directory
-	dir := task.Dir
-	if dir == "" {
-		dir = "."
-	}
-
 	// Create command
 	cmd := exec.Command(r.shell, r.arg, task.Cmd)
 	cmd.Env = env
-	cmd.Dir = dir
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Dir = r.taskDir(task)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.WaitDelay = waitDelay
+	setProcessGroup(cmd)
 
-	return cmd.Run()
-}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
 
-// RunHooks executes a list of commands
-func RunHooks(hooks []string, dir string) error {
-	r := New()
-	for _, cmd := range hooks {
-		task := config.Task{
-			Cmd: cmd,
-			Dir: dir,
-		}
-		if err := r.RunTask(task); err != nil {
-			return fmt.Errorf("failed to run hook %q: %w", cmd, err)
-		}
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		return wrapExitError(err)
+	case <-ctx.Done():
+	}
+
+	// Forward the signal to the whole process group and give it some time
+	// to shut down before killing it
+	_ = signalProcessGroup(cmd.Process, signalFromContext(ctx))
+
+	timer := time.NewTimer(r.grace)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		return wrapExitError(err)
+	case <-timer.C:
+		_ = killProcessGroup(cmd.Process)
+		return wrapExitError(<-done)
 	}
-	return nil
 }
 
 // getShellAndArg returns the appropriate shell and argument for the current OS
@@ -69,4 +192,4 @@ func getShellAndArg() (string, string) {
 		return "cmd", "/C"
 	}
 	return "/bin/sh", "-c"
-} 
\ No newline at end of file
+}
@@ -1,6 +1,8 @@
 package runner
 
 import (
+	"bytes"
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -41,52 +43,17 @@ func TestRunner(t *testing.T) {
 	task := config.Task{
 		Cmd: "cat test.txt",
 	}
-	err = r.RunTask(task)
+	err = r.RunTask(context.Background(), task)
 	assert.NoError(t, err)
 
 	// Run a nonexistent command
 	task = config.Task{
 		Cmd: "nonexistent",
 	}
-	err = r.RunTask(task)
+	err = r.RunTask(context.Background(), task)
 	assert.Error(t, err)
 }
 
-func TestRunHooks(t *testing.T) {
-	// Create a temporary directory
-	tempDir := t.TempDir()
-
-	// Save current directory
-	currentDir, err := os.Getwd()
-	require.NoError(t, err)
-
-	// Create a deferred function to change back to the original directory
-	defer func() {
-		if err := os.Chdir(currentDir); err != nil {
-			t.Errorf("failed to change back to original directory: %v", err)
-		}
-	}()
-
-	// Change to the temporary directory
-	if err := os.Chdir(tempDir); err != nil {
-		t.Fatalf("failed to change to temporary directory: %v", err)
-	}
-
-	// Run hooks
-	hooks := []string{
-		"echo 'test' > test.txt",
-		"invalid_command",
-	}
-
-	err = RunHooks(hooks, tempDir)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "invalid_command")
-
-	// Verify the first hook ran successfully
-	_, err = os.Stat("test.txt")
-	assert.NoError(t, err)
-}
-
 func TestRunTaskWithEnvironment(t *testing.T) {
 	// Create a temporary directory
 	tempDir := t.TempDir()
@@ -117,7 +84,7 @@ func TestRunTaskWithEnvironment(t *testing.T) {
 			"TEST_VALUE": "test_value",
 		},
 	}
-	err = r.RunTask(task)
+	err = r.RunTask(context.Background(), task)
 	assert.NoError(t, err)
 }
 
@@ -140,6 +107,77 @@ func TestRunTaskWithWorkingDirectory(t *testing.T) {
 		Cmd: "ls",
 		Dir: subDir,
 	}
-	err = r.RunTask(task)
+	err = r.RunTask(context.Background(), task)
 	assert.NoError(t, err)
-} 
\ No newline at end of file
+}
+
+func TestRunWithDependencies(t *testing.T) {
+	// Create a temporary directory
+	tempDir := t.TempDir()
+	logFile := filepath.Join(tempDir, "log.txt")
+
+	tasks := map[string]config.Task{
+		"generate": {Cmd: "echo generate >> " + logFile},
+		"build":    {Cmd: "echo build >> " + logFile, Deps: []string{"generate"}},
+		"test":     {Cmd: "echo test >> " + logFile, Deps: []string{"generate"}},
+		"check":    {Cmd: "echo check >> " + logFile, Deps: []string{"build", "test"}},
+	}
+
+	// Run the task graph
+	r := New()
+	err := r.Run(context.Background(), tasks, "check")
+	require.NoError(t, err)
+
+	// Verify each task ran exactly once, after its dependencies
+	content, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+	assert.Equal(t, "generate\nbuild\ntest\ncheck\n", string(content))
+
+	// A failing dependency stops the run
+	tasks["generate"] = config.Task{Cmd: "exit 1"}
+	err = r.Run(context.Background(), tasks, "check")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "generate")
+}
+
+func TestRunParallel(t *testing.T) {
+	// Create a temporary directory
+	tempDir := t.TempDir()
+
+	// Each task waits for the other one to start, so they can only
+	// complete if they run concurrently
+	tasks := map[string]config.Task{
+		"a": {Cmd: "touch a.started; i=0; while [ ! -f b.started ] && [ $i -lt 500 ]; do sleep 0.01; i=$((i+1)); done; test -f b.started && echo done", Dir: tempDir},
+		"b": {Cmd: "touch b.started; i=0; while [ ! -f a.started ] && [ $i -lt 500 ]; do sleep 0.01; i=$((i+1)); done; test -f a.started && echo done", Dir: tempDir},
+	}
+
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	r := New(WithJobs(2), WithOutput(stdout, stderr))
+	err := r.Run(context.Background(), tasks, "a", "b")
+	require.NoError(t, err)
+
+	// Output lines are prefixed with the task name
+	assert.Contains(t, stdout.String(), "[a] done\n")
+	assert.Contains(t, stdout.String(), "[b] done\n")
+}
+
+func TestRunParallelStopsOnFailure(t *testing.T) {
+	// Create a temporary directory
+	tempDir := t.TempDir()
+	logFile := filepath.Join(tempDir, "log.txt")
+
+	tasks := map[string]config.Task{
+		"fail":  {Cmd: "exit 1"},
+		"after": {Cmd: "echo after >> " + logFile, Deps: []string{"fail"}},
+	}
+
+	r := New(WithJobs(4), WithOutput(new(bytes.Buffer), new(bytes.Buffer)))
+	err := r.Run(context.Background(), tasks, "after")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `"fail"`)
+
+	// The dependent task never started
+	_, err = os.Stat(logFile)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,134 @@
+package runner
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/felipevolpatto/genesis/internal/config"
+)
+
+// Plan resolves the named tasks and their dependencies into an execution
+// order in which every task appears exactly once and after all of its
+// dependencies. It returns an error if a task is unknown or if the
+// dependency graph contains a cycle. Tasks referenced from cmds lists are
+// not part of the order since they run as part of the referencing task, but
+// they are checked for cycles as well.
+func Plan(tasks map[string]config.Task, names []string) ([]string, error) {
+	var (
+		order   []string
+		done    = make(map[string]bool)
+		visited = make(map[string]bool)
+		path    []string
+	)
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		if done[name] {
+			return nil
+		}
+
+		if visited[name] {
+			return cycleError(path, name)
+		}
+
+		task, ok := tasks[name]
+		if !ok {
+			if len(path) > 0 {
+				return fmt.Errorf("task %q depends on unknown task %q", path[len(path)-1], name)
+			}
+			return fmt.Errorf("task %q not found", name)
+		}
+
+		visited[name] = true
+		path = append(path, name)
+		for _, dep := range task.Deps {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+
+		done[name] = true
+		order = append(order, name)
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := checkReferences(tasks, names); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// checkReferences verifies that every task referenced from a cmds list
+// exists and that following both deps and references from the named tasks
+// never leads back to a task on the current path
+func checkReferences(tasks map[string]config.Task, names []string) error {
+	var (
+		done    = make(map[string]bool)
+		visited = make(map[string]bool)
+		path    []string
+	)
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		if done[name] {
+			return nil
+		}
+		if visited[name] {
+			return cycleError(path, name)
+		}
+
+		task := tasks[name]
+		visited[name] = true
+		path = append(path, name)
+
+		next := append([]string{}, task.Deps...)
+		for _, c := range task.Cmds {
+			if c.Task == "" {
+				continue
+			}
+			if _, ok := tasks[c.Task]; !ok {
+				return fmt.Errorf("task %q references unknown task %q", name, c.Task)
+			}
+			next = append(next, c.Task)
+		}
+		for _, n := range next {
+			if _, ok := tasks[n]; !ok {
+				return fmt.Errorf("task %q depends on unknown task %q", name, n)
+			}
+			if err := visit(n); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+
+		done[name] = true
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cycleError reports the cycle formed by reaching name again, starting from
+// its first occurrence on the path
+func cycleError(path []string, name string) error {
+	for i, n := range path {
+		if n == name {
+			cycle := append(append([]string{}, path[i:]...), name)
+			return fmt.Errorf("dependency cycle detected: %s", strings.Join(cycle, " -> "))
+		}
+	}
+	return fmt.Errorf("dependency cycle detected at task %q", name)
+}
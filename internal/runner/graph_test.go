@@ -0,0 +1,121 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/felipevolpatto/genesis/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlan(t *testing.T) {
+	tests := []struct {
+		name          string
+		tasks         map[string]config.Task
+		names         []string
+		expectedOrder []string
+		expectedError string
+	}{
+		{
+			name: "single task",
+			tasks: map[string]config.Task{
+				"test": {Cmd: "go test ./..."},
+			},
+			names:         []string{"test"},
+			expectedOrder: []string{"test"},
+		},
+		{
+			name: "dependencies run first",
+			tasks: map[string]config.Task{
+				"lint":  {Cmd: "golangci-lint run"},
+				"test":  {Cmd: "go test ./..."},
+				"check": {Deps: []string{"lint", "test"}},
+			},
+			names:         []string{"check"},
+			expectedOrder: []string{"lint", "test", "check"},
+		},
+		{
+			name: "shared dependency runs once",
+			tasks: map[string]config.Task{
+				"generate": {Cmd: "go generate ./..."},
+				"build":    {Cmd: "go build ./...", Deps: []string{"generate"}},
+				"test":     {Cmd: "go test ./...", Deps: []string{"generate"}},
+				"all":      {Deps: []string{"build", "test"}},
+			},
+			names:         []string{"all"},
+			expectedOrder: []string{"generate", "build", "test", "all"},
+		},
+		{
+			name: "cycle",
+			tasks: map[string]config.Task{
+				"a": {Deps: []string{"b"}},
+				"b": {Deps: []string{"c"}},
+				"c": {Deps: []string{"b"}},
+			},
+			names:         []string{"a"},
+			expectedError: "dependency cycle detected: b -> c -> b",
+		},
+		{
+			name: "self dependency",
+			tasks: map[string]config.Task{
+				"a": {Deps: []string{"a"}},
+			},
+			names:         []string{"a"},
+			expectedError: "dependency cycle detected: a -> a",
+		},
+		{
+			name: "unknown dependency",
+			tasks: map[string]config.Task{
+				"a": {Deps: []string{"missing"}},
+			},
+			names:         []string{"a"},
+			expectedError: `task "a" depends on unknown task "missing"`,
+		},
+		{
+			name: "referenced tasks are not planned",
+			tasks: map[string]config.Task{
+				"lint":  {Cmd: "golangci-lint run"},
+				"check": {Cmds: []config.Command{{Task: "lint"}, {Cmd: "go test ./..."}}},
+			},
+			names:         []string{"check"},
+			expectedOrder: []string{"check"},
+		},
+		{
+			name: "cycle through reference",
+			tasks: map[string]config.Task{
+				"a": {Deps: []string{"b"}},
+				"b": {Cmds: []config.Command{{Task: "a"}}},
+			},
+			names:         []string{"a"},
+			expectedError: "dependency cycle detected: a -> b -> a",
+		},
+		{
+			name: "unknown reference",
+			tasks: map[string]config.Task{
+				"a": {Cmds: []config.Command{{Task: "missing"}}},
+			},
+			names:         []string{"a"},
+			expectedError: `task "a" references unknown task "missing"`,
+		},
+		{
+			name:          "unknown task",
+			tasks:         map[string]config.Task{},
+			names:         []string{"missing"},
+			expectedError: `task "missing" not found`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := Plan(tt.tasks, tt.names)
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedOrder, order)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/felipevolpatto/genesis/internal/config"
+)
+
+// unsafeEnvChars matches characters that are not allowed in environment
+// variable names
+var unsafeEnvChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// RunHooks executes a list of commands in dir with env added to the
+// environment
+func RunHooks(hooks []string, dir string, env map[string]string) error {
+	r := New()
+	for _, cmd := range hooks {
+		task := config.Task{
+			Cmd: cmd,
+			Dir: dir,
+			Env: env,
+		}
+		if err := r.RunTask(context.Background(), task); err != nil {
+			return fmt.Errorf("failed to run hook %q: %w", cmd, err)
+		}
+	}
+	return nil
+}
+
+// HookEnv returns the environment variables available to template hooks.
+// Every template variable is exported under its name in uppercase, with
+// characters other than letters, digits and underscores replaced by
+// underscores, together with GENESIS_PROJECT_NAME, GENESIS_TEMPLATE_DIR and
+// GENESIS_TARGET_DIR. Lists are exported as comma-separated values.
+func HookEnv(vars map[string]interface{}, projectName, templateDir, targetDir string) map[string]string {
+	env := make(map[string]string, len(vars)+3)
+	for name, value := range vars {
+		env[EnvName(name)] = envValue(value)
+	}
+	env["GENESIS_PROJECT_NAME"] = projectName
+	env["GENESIS_TEMPLATE_DIR"] = templateDir
+	env["GENESIS_TARGET_DIR"] = targetDir
+	return env
+}
+
+// EnvName returns the name under which a template variable is exported to
+// hooks, e.g. go_version becomes GO_VERSION
+func EnvName(name string) string {
+	return strings.ToUpper(unsafeEnvChars.ReplaceAllString(name, "_"))
+}
+
+// envValue formats a variable value for the environment
+func envValue(value interface{}) string {
+	if list, ok := value.([]string); ok {
+		return strings.Join(list, ",")
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,84 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunHooks(t *testing.T) {
+	// Create a temporary directory
+	tempDir := t.TempDir()
+
+	// Save current directory
+	currentDir, err := os.Getwd()
+	require.NoError(t, err)
+
+	// Create a deferred function to change back to the original directory
+	defer func() {
+		if err := os.Chdir(currentDir); err != nil {
+			t.Errorf("failed to change back to original directory: %v", err)
+		}
+	}()
+
+	// Change to the temporary directory
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("failed to change to temporary directory: %v", err)
+	}
+
+	// Run hooks
+	hooks := []string{
+		"echo 'test' > test.txt",
+		"invalid_command",
+	}
+
+	err = RunHooks(hooks, tempDir, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid_command")
+
+	// Verify the first hook ran successfully
+	_, err = os.Stat("test.txt")
+	assert.NoError(t, err)
+}
+
+func TestRunHooksWithEnvironment(t *testing.T) {
+	tempDir := t.TempDir()
+	env := HookEnv(map[string]interface{}{"go_version": "1.21"}, "demo", "/tmp/template", tempDir)
+
+	hooks := []string{`echo "$GENESIS_PROJECT_NAME $GO_VERSION $GENESIS_TARGET_DIR" > env.txt`}
+	err := RunHooks(hooks, tempDir, env)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "env.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "demo 1.21 "+tempDir+"\n", string(content))
+}
+
+func TestHookEnv(t *testing.T) {
+	vars := map[string]interface{}{
+		"name":         "demo",
+		"go_version":   "1.21",
+		"module-path":  "github.com/example/demo",
+		"Docker.Image": "alpine",
+		"use_docker":   true,
+		"port":         8080,
+		"features":     []string{"ci", "docs"},
+	}
+
+	env := HookEnv(vars, "demo", "/tmp/template", "/tmp/demo")
+	assert.Equal(t, map[string]string{
+		"NAME":                 "demo",
+		"GO_VERSION":           "1.21",
+		"MODULE_PATH":          "github.com/example/demo",
+		"DOCKER_IMAGE":         "alpine",
+		"USE_DOCKER":           "true",
+		"PORT":                 "8080",
+		"FEATURES":             "ci,docs",
+		"GENESIS_PROJECT_NAME": "demo",
+		"GENESIS_TEMPLATE_DIR": "/tmp/template",
+		"GENESIS_TARGET_DIR":   "/tmp/demo",
+	}, env)
+}
@@ -0,0 +1,100 @@
+package runner
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// prefixColors are the ANSI color codes cycled through for task prefixes
+var prefixColors = []int{36, 33, 32, 35, 34, 31}
+
+// newPrefixes builds the output prefix for each task name. Names are padded
+// to the same width so that the output of parallel tasks lines up.
+func newPrefixes(names []string, color bool) map[string]string {
+	width := 0
+	for _, name := range names {
+		if len(name) > width {
+			width = len(name)
+		}
+	}
+
+	prefixes := make(map[string]string, len(names))
+	for i, name := range names {
+		prefix := fmt.Sprintf("[%s]%*s ", name, width-len(name), "")
+		if color {
+			prefix = fmt.Sprintf("\x1b[%dm%s\x1b[0m", prefixColors[i%len(prefixColors)], prefix)
+		}
+		prefixes[name] = prefix
+	}
+	return prefixes
+}
+
+// prefixWriter buffers output and writes it line by line, each line preceded
+// by a prefix. Writes to the underlying writer are serialized through a
+// shared mutex so lines from concurrent tasks never interleave.
+type prefixWriter struct {
+	w      io.Writer
+	prefix string
+	mu     *sync.Mutex
+	buf    bytes.Buffer
+}
+
+// newPrefixWriter creates a prefixWriter writing to w
+func newPrefixWriter(w io.Writer, prefix string, mu *sync.Mutex) *prefixWriter {
+	return &prefixWriter{
+		w:      w,
+		prefix: prefix,
+		mu:     mu,
+	}
+}
+
+// Write buffers p and writes out every complete line
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.buf.Write(b)
+	for {
+		i := bytes.IndexByte(p.buf.Bytes(), '\n')
+		if i < 0 {
+			break
+		}
+		if err := p.writeLine(p.buf.Next(i + 1)); err != nil {
+			return len(b), err
+		}
+	}
+	return len(b), nil
+}
+
+// Flush writes out any remaining partial line
+func (p *prefixWriter) Flush() {
+	if p.buf.Len() == 0 {
+		return
+	}
+	line := append(p.buf.Bytes(), '\n')
+	p.buf.Reset()
+	_ = p.writeLine(line)
+}
+
+// writeLine writes a single prefixed line
+func (p *prefixWriter) writeLine(line []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err := fmt.Fprintf(p.w, "%s%s", p.prefix, line)
+	return err
+}
+
+// writeSummary prints a table with the status and duration of every task
+func writeSummary(w io.Writer, results []Result) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "\nTASK\tSTATUS\tDURATION")
+	for _, res := range results {
+		duration := "-"
+		if res.Status == StatusPassed || res.Status == StatusFailed {
+			duration = res.Duration.Round(time.Millisecond).String()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", res.Task, res.Status, duration)
+	}
+	tw.Flush()
+}
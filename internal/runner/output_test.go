@@ -0,0 +1,36 @@
+package runner
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrefixWriter(t *testing.T) {
+	buf := new(bytes.Buffer)
+	w := newPrefixWriter(buf, "[test] ", &sync.Mutex{})
+
+	// Partial lines are held back until they are complete
+	_, err := w.Write([]byte("hello"))
+	assert.NoError(t, err)
+	assert.Empty(t, buf.String())
+
+	_, err = w.Write([]byte(" world\nsecond line\nthird"))
+	assert.NoError(t, err)
+	assert.Equal(t, "[test] hello world\n[test] second line\n", buf.String())
+
+	// Flush writes out the remaining partial line
+	w.Flush()
+	assert.Equal(t, "[test] hello world\n[test] second line\n[test] third\n", buf.String())
+}
+
+func TestNewPrefixes(t *testing.T) {
+	prefixes := newPrefixes([]string{"lint", "build"}, false)
+	assert.Equal(t, "[lint]  ", prefixes["lint"])
+	assert.Equal(t, "[build] ", prefixes["build"])
+
+	prefixes = newPrefixes([]string{"lint"}, true)
+	assert.Equal(t, "\x1b[36m[lint] \x1b[0m", prefixes["lint"])
+}
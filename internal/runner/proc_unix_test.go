@@ -0,0 +1,130 @@
+//go:build !windows
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/felipevolpatto/genesis/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// processAlive reports whether a process exists and is not a zombie
+func processAlive(pid int) bool {
+	if stat, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat")); err == nil {
+		fields := strings.Fields(string(stat[strings.LastIndexByte(string(stat), ')')+1:]))
+		return len(fields) > 0 && fields[0] != "Z"
+	}
+	return syscall.Kill(pid, 0) == nil
+}
+
+// readPid waits for a task to write a process id to path
+func readPid(t *testing.T, path string) int {
+	var pid int
+	require.Eventually(t, func() bool {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return false
+		}
+		pid, err = strconv.Atoi(strings.TrimSpace(string(content)))
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	return pid
+}
+
+// runInBackground runs a task and returns a channel that receives its error
+func runInBackground(ctx context.Context, r *Runner, task config.Task) <-chan error {
+	errc := make(chan error, 1)
+	go func() {
+		errc <- r.RunTask(ctx, task)
+	}()
+	return errc
+}
+
+// waitErr waits for a task started by runInBackground to return
+func waitErr(t *testing.T, errc <-chan error) error {
+	select {
+	case err := <-errc:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("task did not stop")
+		return nil
+	}
+}
+
+func TestRunTaskCancelStopsProcessGroup(t *testing.T) {
+	dir := t.TempDir()
+	pidFile := filepath.Join(dir, "child.pid")
+
+	// The background child would be orphaned if only the shell was killed
+	task := config.Task{Cmd: "sleep 30 & echo $! > " + pidFile + "; wait"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := runInBackground(ctx, New(), task)
+	pid := readPid(t, pidFile)
+
+	cancel()
+	err := waitErr(t, errc)
+
+	var exitErr *ExitError
+	require.True(t, errors.As(err, &exitErr))
+	assert.Equal(t, 128+int(syscall.SIGTERM), exitErr.Code)
+	assert.Eventually(t, func() bool { return !processAlive(pid) }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestRunTaskKillsAfterGracePeriod(t *testing.T) {
+	// The task and its children ignore SIGTERM
+	task := config.Task{Cmd: "trap '' TERM; while true; do sleep 0.05; done"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := runInBackground(ctx, New(WithGracePeriod(200*time.Millisecond)), task)
+
+	time.Sleep(100 * time.Millisecond)
+	start := time.Now()
+	cancel()
+	err := waitErr(t, errc)
+
+	var exitErr *ExitError
+	require.True(t, errors.As(err, &exitErr))
+	assert.Equal(t, 128+int(syscall.SIGKILL), exitErr.Code)
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+}
+
+func TestInterruptContextForwardsSignal(t *testing.T) {
+	dir := t.TempDir()
+	pidFile := filepath.Join(dir, "task.pid")
+
+	ctx, stop := InterruptContext(context.Background())
+	defer stop()
+
+	task := config.Task{Cmd: "echo $$ > " + pidFile + "; sleep 30"}
+	errc := runInBackground(ctx, New(), task)
+	readPid(t, pidFile)
+
+	// Simulate Ctrl-C on genesis itself
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGINT))
+	err := waitErr(t, errc)
+
+	assert.Equal(t, os.Interrupt, signalFromContext(ctx))
+
+	var exitErr *ExitError
+	require.True(t, errors.As(err, &exitErr))
+	assert.Equal(t, 130, exitErr.Code)
+}
+
+func TestRunTaskWithCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := New().RunTask(ctx, config.Task{Cmd: "echo never"})
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,26 @@
+//go:build windows
+
+package runner
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup starts the command in a new process group so that console
+// interrupts aimed at genesis are not delivered to it directly
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// signalProcessGroup stops the process. Windows cannot deliver signals to
+// other processes, so this is the same as killing it.
+func signalProcessGroup(p *os.Process, sig os.Signal) error {
+	return p.Kill()
+}
+
+// killProcessGroup kills the process
+func killProcessGroup(p *os.Process) error {
+	return p.Kill()
+}
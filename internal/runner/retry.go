@@ -0,0 +1,76 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/felipevolpatto/genesis/internal/config"
+)
+
+// execWithRetries executes a task, killing attempts that exceed the task's
+// timeout and retrying failed attempts as many times as the task allows.
+// The delay between attempts starts at the task's backoff and doubles after
+// every retry.
+func (r *Runner) execWithRetries(ctx context.Context, name string, task config.Task, stdout, stderr io.Writer) error {
+	timeout, err := task.TimeoutDuration()
+	if err != nil {
+		return fmt.Errorf("invalid timeout: %w", err)
+	}
+	backoff, err := task.BackoffDuration()
+	if err != nil {
+		return fmt.Errorf("invalid backoff: %w", err)
+	}
+
+	attempts := task.Retries + 1
+	for attempt := 1; ; attempt++ {
+		err = r.execAttempt(ctx, task, timeout, stdout, stderr)
+		if err == nil {
+			if attempt > 1 {
+				fmt.Fprintf(stderr, "task %s succeeded on attempt %d/%d\n", name, attempt, attempts)
+			}
+			return nil
+		}
+
+		var timeoutErr *TimeoutError
+		if errors.As(err, &timeoutErr) {
+			fmt.Fprintf(stderr, "task %s timed out after %s\n", name, timeoutErr.Timeout)
+		}
+
+		if attempt >= attempts || ctx.Err() != nil {
+			return err
+		}
+
+		fmt.Fprintf(stderr, "task %s failed on attempt %d/%d: %v, retrying", name, attempt, attempts, err)
+		if backoff > 0 {
+			fmt.Fprintf(stderr, " in %s", backoff)
+		}
+		fmt.Fprintln(stderr)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// execAttempt executes a task once, stopping it if it runs longer than the
+// timeout. A zero timeout means no limit.
+func (r *Runner) execAttempt(ctx context.Context, task config.Task, timeout time.Duration, stdout, stderr io.Writer) error {
+	if timeout <= 0 {
+		return r.execTask(ctx, task, stdout, stderr)
+	}
+
+	attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := r.execTask(attemptCtx, task, stdout, stderr)
+	if err != nil && ctx.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+		return &TimeoutError{Timeout: timeout, Err: err}
+	}
+	return err
+}
@@ -0,0 +1,112 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/felipevolpatto/genesis/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyCmd returns a command that fails until it has been run n times
+func flakyCmd(dir string, n int) string {
+	count := filepath.Join(dir, "count")
+	return `n=$(cat ` + count + ` 2>/dev/null || echo 0); n=$((n+1)); echo $n > ` + count + `; [ $n -ge ` + strconv.Itoa(n) + ` ]`
+}
+
+func TestRunRetries(t *testing.T) {
+	tests := []struct {
+		name        string
+		retries     int
+		expectError bool
+		contains    string
+	}{
+		{
+			name:     "succeeds on last attempt",
+			retries:  2,
+			contains: "task flaky succeeded on attempt 3/3",
+		},
+		{
+			name:        "runs out of attempts",
+			retries:     1,
+			expectError: true,
+			contains:    "task flaky failed on attempt 1/2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks := map[string]config.Task{
+				"flaky": {Cmd: flakyCmd(t.TempDir(), 3), Retries: tt.retries},
+			}
+
+			stderr := new(bytes.Buffer)
+			r := New(WithOutput(new(bytes.Buffer), stderr))
+			err := r.Run(context.Background(), tasks, "flaky")
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Contains(t, stderr.String(), tt.contains)
+		})
+	}
+}
+
+func TestRunRetriesWithBackoff(t *testing.T) {
+	tasks := map[string]config.Task{
+		"flaky": {Cmd: flakyCmd(t.TempDir(), 3), Retries: 2, Backoff: "50ms"},
+	}
+
+	stderr := new(bytes.Buffer)
+	start := time.Now()
+	err := New(WithOutput(new(bytes.Buffer), stderr)).Run(context.Background(), tasks, "flaky")
+	require.NoError(t, err)
+
+	// The delay doubles after each retry: 50ms + 100ms
+	assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+	assert.Contains(t, stderr.String(), "retrying in 50ms")
+	assert.Contains(t, stderr.String(), "retrying in 100ms")
+}
+
+func TestRunTimeout(t *testing.T) {
+	tasks := map[string]config.Task{
+		"hang": {Cmd: "sleep 5", Timeout: "100ms"},
+	}
+
+	stderr := new(bytes.Buffer)
+	start := time.Now()
+	err := New(WithOutput(new(bytes.Buffer), stderr)).Run(context.Background(), tasks, "hang")
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 3*time.Second)
+	assert.Contains(t, stderr.String(), "task hang timed out after 100ms")
+
+	var timeoutErr *TimeoutError
+	require.True(t, errors.As(err, &timeoutErr))
+	assert.Equal(t, 100*time.Millisecond, timeoutErr.Timeout)
+
+	// The process was terminated, which is reflected in the exit code
+	var exitErr *ExitError
+	require.True(t, errors.As(err, &exitErr))
+	assert.Equal(t, 143, exitErr.Code)
+}
+
+func TestRunTimeoutIsRetried(t *testing.T) {
+	dir := t.TempDir()
+	tasks := map[string]config.Task{
+		// Hangs on the first attempt only
+		"hang": {Cmd: flakyCmd(dir, 2) + " || sleep 5", Timeout: "200ms", Retries: 1},
+	}
+
+	stderr := new(bytes.Buffer)
+	err := New(WithOutput(new(bytes.Buffer), stderr)).Run(context.Background(), tasks, "hang")
+	require.NoError(t, err)
+	assert.Contains(t, stderr.String(), "task hang timed out after 200ms")
+	assert.Contains(t, stderr.String(), "task hang succeeded on attempt 2/2")
+}
@@ -0,0 +1,235 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/felipevolpatto/genesis/internal/config"
+)
+
+// Status is the outcome of a task in a run
+type Status string
+
+// Statuses a task can end a run with
+const (
+	StatusPassed   Status = "passed"
+	StatusFailed   Status = "failed"
+	StatusSkipped  Status = "skipped"
+	StatusUpToDate Status = "up to date"
+)
+
+// Result describes how a single task ended
+type Result struct {
+	Task     string
+	Status   Status
+	Duration time.Duration
+	Err      error
+}
+
+// ok reports whether dependents of the task may run
+func (s Status) ok() bool {
+	return s == StatusPassed || s == StatusUpToDate
+}
+
+// Run executes the named tasks together with their dependencies. Each task
+// runs at most once per invocation, after all of its dependencies succeeded.
+// Independent tasks run concurrently when the Runner allows more than one job.
+// Run stops at the first failure unless the Runner continues on error, in
+// which case it runs every task it can and prints a summary at the end.
+//
+// Every task runs in its own process group. When ctx is cancelled, the
+// signal that caused it (see InterruptContext), or SIGTERM otherwise, is
+// forwarded to the group, which is killed if it has not exited after the
+// grace period.
+func (r *Runner) Run(ctx context.Context, tasks map[string]config.Task, names ...string) error {
+	order, err := Plan(tasks, names)
+	if err != nil {
+		return err
+	}
+
+	tasks = r.resolveArgs(tasks, names)
+
+	var results []Result
+	if r.jobs == 1 {
+		results = r.runSequential(ctx, tasks, order)
+	} else {
+		results = r.runParallel(ctx, tasks, order)
+	}
+
+	if r.keepGoing {
+		writeSummary(r.stdout, results)
+	}
+
+	var errs []error
+	for _, res := range results {
+		if res.Status == StatusFailed {
+			errs = append(errs, fmt.Errorf("failed to run task %q: %w", res.Task, res.Err))
+		}
+	}
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return errors.Join(errs...)
+}
+
+// runSequential runs the planned tasks one at a time, in order
+func (r *Runner) runSequential(ctx context.Context, tasks map[string]config.Task, order []string) []Result {
+	var results []Result
+	status := make(map[string]Status)
+
+	for _, name := range order {
+		var res Result
+		if !depsPassed(tasks[name], status) {
+			res = Result{Task: name, Status: StatusSkipped}
+		} else {
+			res = r.runResult(ctx, tasks, name, r.stdout, r.stderr)
+		}
+
+		status[name] = res.Status
+		results = append(results, res)
+
+		if res.Status == StatusFailed && !r.keepGoing {
+			break
+		}
+	}
+
+	return results
+}
+
+// runParallel runs the planned tasks with up to r.jobs tasks at a time. A
+// task starts as soon as all of its dependencies have finished. After the
+// first failure no new tasks are started, unless the Runner continues on
+// error, but running ones are always waited for.
+func (r *Runner) runParallel(ctx context.Context, tasks map[string]config.Task, order []string) []Result {
+	prefixes := newPrefixes(order, r.color)
+	pending := append([]string{}, order...)
+	status := make(map[string]Status)
+	finished := make(chan Result)
+	running := 0
+	stopped := false
+
+	var results []Result
+	for {
+		for i := 0; !stopped && running < r.jobs && i < len(pending); {
+			name := pending[i]
+			if !depsFinished(tasks[name], status) {
+				i++
+				continue
+			}
+
+			pending = append(pending[:i], pending[i+1:]...)
+
+			// A failed dependency means the task cannot run. Its dependents
+			// may now be ready to be skipped as well, so scan again.
+			if !depsPassed(tasks[name], status) {
+				status[name] = StatusSkipped
+				results = append(results, Result{Task: name, Status: StatusSkipped})
+				i = 0
+				continue
+			}
+
+			running++
+			go func(name string) {
+				finished <- r.runPrefixed(ctx, tasks, name, prefixes[name])
+			}(name)
+		}
+
+		if running == 0 {
+			break
+		}
+
+		res := <-finished
+		running--
+		status[res.Task] = res.Status
+		results = append(results, res)
+		if res.Status == StatusFailed && !r.keepGoing {
+			stopped = true
+		}
+	}
+
+	// Report results in plan order rather than completion order
+	index := make(map[string]int, len(order))
+	for i, name := range order {
+		index[name] = i
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return index[results[i].Task] < index[results[j].Task]
+	})
+
+	return results
+}
+
+// runResult runs a task and records its outcome and duration
+func (r *Runner) runResult(ctx context.Context, tasks map[string]config.Task, name string, stdout, stderr io.Writer) Result {
+	start := time.Now()
+	status, err := r.runNamed(ctx, tasks, name, stdout, stderr)
+	return Result{
+		Task:     name,
+		Status:   status,
+		Duration: time.Since(start),
+		Err:      err,
+	}
+}
+
+// runNamed runs a task unless its checksum shows it is up to date. The
+// checksum is taken before the run and recorded after it succeeds, so
+// sources changed while the task runs are picked up by the next run.
+func (r *Runner) runNamed(ctx context.Context, tasks map[string]config.Task, name string, stdout, stderr io.Writer) (Status, error) {
+	task := tasks[name]
+	sum, err := r.checksum(task)
+	if err != nil {
+		return StatusFailed, err
+	}
+	upToDate, err := r.upToDate(name, task, sum)
+	if err != nil {
+		return StatusFailed, err
+	}
+	if upToDate {
+		fmt.Fprintf(stderr, "task %s is up to date\n", name)
+		return StatusUpToDate, nil
+	}
+
+	if err := r.execCommands(ctx, tasks, name, stdout, stderr); err != nil {
+		return StatusFailed, err
+	}
+
+	if err := r.storeChecksum(name, sum); err != nil {
+		return StatusFailed, err
+	}
+
+	return StatusPassed, nil
+}
+
+// runPrefixed runs a task with every line of its output prefixed
+func (r *Runner) runPrefixed(ctx context.Context, tasks map[string]config.Task, name string, prefix string) Result {
+	stdout := newPrefixWriter(r.stdout, prefix, &r.mu)
+	stderr := newPrefixWriter(r.stderr, prefix, &r.mu)
+	res := r.runResult(ctx, tasks, name, stdout, stderr)
+	stdout.Flush()
+	stderr.Flush()
+	return res
+}
+
+// depsFinished reports whether all dependencies of a task have finished
+func depsFinished(task config.Task, status map[string]Status) bool {
+	for _, dep := range task.Deps {
+		if _, ok := status[dep]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// depsPassed reports whether all dependencies of a task finished successfully
+func depsPassed(task config.Task, status map[string]Status) bool {
+	for _, dep := range task.Deps {
+		if !status[dep].ok() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/felipevolpatto/genesis/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunStopsAtFirstFailure(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "log.txt")
+
+	tasks := map[string]config.Task{
+		"lint":  {Cmd: "echo lint >> " + logFile},
+		"test":  {Cmd: "exit 1"},
+		"build": {Cmd: "echo build >> " + logFile},
+	}
+
+	stdout := new(bytes.Buffer)
+	r := New(WithOutput(stdout, new(bytes.Buffer)))
+	err := r.Run(context.Background(), tasks, "lint", "test", "build")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `failed to run task "test"`)
+
+	// Tasks after the failure did not run and no summary is printed
+	content, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+	assert.Equal(t, "lint\n", string(content))
+	assert.NotContains(t, stdout.String(), "STATUS")
+}
+
+func TestRunContinueOnError(t *testing.T) {
+	tests := []struct {
+		name string
+		jobs int
+	}{
+		{name: "sequential", jobs: 1},
+		{name: "parallel", jobs: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logFile := filepath.Join(t.TempDir(), "log.txt")
+
+			tasks := map[string]config.Task{
+				"lint":    {Cmd: "exit 2"},
+				"test":    {Cmd: "echo test >> " + logFile},
+				"build":   {Cmd: "echo build >> " + logFile},
+				"release": {Cmd: "echo release >> " + logFile, Deps: []string{"lint", "build"}},
+				"publish": {Cmd: "echo publish >> " + logFile, Deps: []string{"release"}},
+			}
+
+			stdout := new(bytes.Buffer)
+			r := New(WithJobs(tt.jobs), WithContinueOnError(true), WithOutput(stdout, new(bytes.Buffer)))
+			err := r.Run(context.Background(), tasks, "lint", "test", "publish")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), `failed to run task "lint"`)
+
+			// Tasks that do not depend on the failure still ran
+			content, err := os.ReadFile(logFile)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []string{"test", "build"}, strings.Fields(string(content)))
+
+			// The summary lists every task in plan order
+			output := stdout.String()
+			summary := output[strings.Index(output, "TASK"):]
+			lines := strings.Split(strings.TrimSpace(summary), "\n")
+			require.Len(t, lines, 6)
+			assert.Regexp(t, `^TASK\s+STATUS\s+DURATION$`, lines[0])
+			assert.Regexp(t, `^lint\s+failed\s+\S+$`, lines[1])
+			assert.Regexp(t, `^test\s+passed\s+\S+$`, lines[2])
+			assert.Regexp(t, `^build\s+passed\s+\S+$`, lines[3])
+			assert.Regexp(t, `^release\s+skipped\s+-$`, lines[4])
+			assert.Regexp(t, `^publish\s+skipped\s+-$`, lines[5])
+		})
+	}
+}
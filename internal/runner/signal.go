@@ -0,0 +1,54 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// interruptError is the cancellation cause of a context cancelled by a signal
+type interruptError struct {
+	sig os.Signal
+}
+
+// Error describes the received signal
+func (e *interruptError) Error() string {
+	return fmt.Sprintf("received signal: %v", e.sig)
+}
+
+// InterruptContext returns a copy of parent that is cancelled when the
+// process receives SIGINT or SIGTERM. The signal is recorded as the
+// cancellation cause so that it can be forwarded to running tasks. Calling
+// the returned function stops listening for signals.
+func InterruptContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case sig := <-signals:
+			cancel(&interruptError{sig: sig})
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(signals)
+		cancel(context.Canceled)
+	}
+}
+
+// signalFromContext returns the signal that cancelled ctx, or SIGTERM if it
+// was cancelled for another reason
+func signalFromContext(ctx context.Context) os.Signal {
+	var ie *interruptError
+	if errors.As(context.Cause(ctx), &ie) {
+		return ie.sig
+	}
+	return syscall.SIGTERM
+}
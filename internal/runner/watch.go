@@ -0,0 +1,174 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/felipevolpatto/genesis/internal/config"
+	"github.com/felipevolpatto/genesis/internal/glob"
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultDebounce is how long Watch waits for file events to settle before
+// restarting a task
+const DefaultDebounce = 200 * time.Millisecond
+
+// WithDebounce sets how long Watch waits after the last file event before
+// restarting a task
+func WithDebounce(d time.Duration) Option {
+	return func(r *Runner) {
+		r.debounce = d
+	}
+}
+
+// Watch runs the named task and runs it again whenever a file matching its
+// sources changes. Bursts of events are debounced, and a run that is still
+// in progress is killed before the task restarts. Watch returns when ctx is
+// cancelled.
+func (r *Runner) Watch(ctx context.Context, tasks map[string]config.Task, name string) error {
+	task, ok := tasks[name]
+	if !ok {
+		return fmt.Errorf("task %q not found", name)
+	}
+	if len(task.Sources) == 0 {
+		return fmt.Errorf("task %q has no sources to watch", name)
+	}
+
+	// Validate the graph before watching anything
+	if _, err := Plan(tasks, []string{name}); err != nil {
+		return err
+	}
+
+	dir, err := filepath.Abs(r.taskDir(task))
+	if err != nil {
+		return fmt.Errorf("failed to resolve task directory: %w", err)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	if err := watchTree(watcher, dir); err != nil {
+		return err
+	}
+
+	var (
+		cancelRun context.CancelFunc
+		runDone   chan struct{}
+		timer     = time.NewTimer(0)
+	)
+
+	// stop kills the run in progress and waits for it to exit
+	stop := func() {
+		if cancelRun != nil {
+			cancelRun()
+			<-runDone
+			cancelRun = nil
+		}
+	}
+	defer stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			// Watch directories created after startup
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := watchTree(watcher, event.Name); err != nil {
+						fmt.Fprintf(r.stderr, "Warning: %v\n", err)
+					}
+				}
+			}
+
+			relPath, err := filepath.Rel(dir, event.Name)
+			if err != nil || !matchesAny(task.Sources, filepath.ToSlash(relPath)) {
+				continue
+			}
+
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(r.debounce)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(r.stderr, "Warning: watcher error: %v\n", err)
+
+		case <-timer.C:
+			if cancelRun != nil {
+				fmt.Fprintf(r.stderr, "Change detected, restarting task %s\n", name)
+			}
+			stop()
+
+			runCtx, cancel := context.WithCancel(ctx)
+			done := make(chan struct{})
+			cancelRun, runDone = cancel, done
+			go func() {
+				defer close(done)
+				err := r.Run(runCtx, tasks, name)
+				if runCtx.Err() != nil {
+					return
+				}
+				if err != nil {
+					fmt.Fprintf(r.stderr, "Error: %v\n", err)
+				}
+				fmt.Fprintf(r.stderr, "Watching for changes...\n")
+			}()
+		}
+	}
+}
+
+// watchTree adds dir and all of its subdirectories to the watcher
+func watchTree(watcher *fsnotify.Watcher, dir string) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+
+		if !info.IsDir() {
+			return nil
+		}
+
+		// Skip VCS and checksum directories
+		if path != dir && (info.Name() == ".git" || info.Name() == ".genesis") {
+			return filepath.SkipDir
+		}
+
+		if err := watcher.Add(path); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+// matchesAny reports whether name matches at least one of the patterns
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if glob.Match(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package runner
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/felipevolpatto/genesis/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readLines returns the lines written to a log file so far
+func readLines(t *testing.T, path string) []string {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	require.NoError(t, err)
+	return strings.Fields(string(content))
+}
+
+// startWatch runs Watch in the background and returns a function that stops it
+func startWatch(t *testing.T, tasks map[string]config.Task, name string) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+
+	r := New(WithOutput(io.Discard, io.Discard), WithDebounce(50*time.Millisecond))
+	go func() {
+		errc <- r.Watch(ctx, tasks, name)
+	}()
+
+	return func() {
+		cancel()
+		select {
+		case err := <-errc:
+			assert.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Error("watch did not stop")
+		}
+	}
+}
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	logFile := filepath.Join(t.TempDir(), "log.txt")
+	source := filepath.Join(dir, "main.go")
+	require.NoError(t, os.WriteFile(source, []byte("package main"), 0644))
+
+	tasks := map[string]config.Task{
+		"test": {Cmd: "echo run >> " + logFile, Dir: dir, Sources: []string{"**/*.go"}},
+	}
+
+	stop := startWatch(t, tasks, "test")
+	defer stop()
+
+	// The task runs once on startup
+	require.Eventually(t, func() bool { return len(readLines(t, logFile)) == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	// A burst of changes results in a single run
+	for i := 0; i < 5; i++ {
+		require.NoError(t, os.WriteFile(source, []byte("package main // "+strings.Repeat("x", i)), 0644))
+	}
+	require.Eventually(t, func() bool { return len(readLines(t, logFile)) == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	// Files that do not match the sources are ignored
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("notes"), 0644))
+
+	// Files in new directories are picked up
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "pkg"), 0755))
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pkg", "pkg.go"), []byte("package pkg"), 0644))
+	require.Eventually(t, func() bool { return len(readLines(t, logFile)) == 3 }, 5*time.Second, 10*time.Millisecond)
+
+	time.Sleep(200 * time.Millisecond)
+	assert.Len(t, readLines(t, logFile), 3)
+}
+
+func TestWatchRestartsRunningTask(t *testing.T) {
+	dir := t.TempDir()
+	logFile := filepath.Join(t.TempDir(), "log.txt")
+	source := filepath.Join(dir, "main.go")
+	require.NoError(t, os.WriteFile(source, []byte("package main"), 0644))
+
+	tasks := map[string]config.Task{
+		"dev": {Cmd: "echo start >> " + logFile + " && sleep 2 && echo end >> " + logFile, Dir: dir, Sources: []string{"*.go"}},
+	}
+
+	stop := startWatch(t, tasks, "dev")
+
+	require.Eventually(t, func() bool { return len(readLines(t, logFile)) == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	// Changing a source kills the running task and starts it again
+	require.NoError(t, os.WriteFile(source, []byte("package main // changed"), 0644))
+	require.Eventually(t, func() bool { return len(readLines(t, logFile)) == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	stop()
+	time.Sleep(2500 * time.Millisecond)
+	assert.Equal(t, []string{"start", "start"}, readLines(t, logFile))
+}
+
+func TestWatchErrors(t *testing.T) {
+	tasks := map[string]config.Task{
+		"build": {Cmd: "go build"},
+	}
+
+	r := New()
+	err := r.Watch(context.Background(), tasks, "missing")
+	assert.Error(t, err)
+
+	err = r.Watch(context.Background(), tasks, "build")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no sources to watch")
+}
@@ -18,10 +18,11 @@ func CloneTemplate(url string, version string) (string, error) {
 		return "", fmt.Errorf("failed to create temp directory: %w", err)
 	}
 
-	// Clone options
+	// Clone options; progress goes to stderr so that it does not mix with
+	// command output such as diffs
 	options := &git.CloneOptions{
 		URL:      url,
-		Progress: os.Stdout,
+		Progress: os.Stderr,
 	}
 
 	// Clone the repository
@@ -84,6 +85,22 @@ func CloneTemplate(url string, version string) (string, error) {
 	return tempDir, nil
 }
 
+// TemplateCommit returns the hash of the commit checked out in a template
+// cloned with CloneTemplate
+func TemplateCommit(dir string) (string, error) {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("failed to get HEAD: %w", err)
+	}
+
+	return head.Hash().String(), nil
+}
+
 // CleanupTemplate removes the temporary directory
 func CleanupTemplate(dir string) error {
 	return os.RemoveAll(dir)
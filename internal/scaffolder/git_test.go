@@ -289,4 +289,23 @@ func TestCloneTemplateWithDifferentVersions(t *testing.T) {
 			assert.NoError(t, err)
 		})
 	}
-} 
\ No newline at end of file
+}
+
+func TestTemplateCommit(t *testing.T) {
+	repoDir, commit := setupTestRepo(t)
+
+	for _, version := range []string{"", "v1.0.0", "master", commit.String()} {
+		t.Run(version, func(t *testing.T) {
+			dir, err := CloneTemplate(repoDir, version)
+			require.NoError(t, err)
+			defer CleanupTemplate(dir)
+
+			hash, err := TemplateCommit(dir)
+			require.NoError(t, err)
+			assert.Equal(t, commit.String(), hash)
+		})
+	}
+
+	_, err := TemplateCommit(t.TempDir())
+	assert.Error(t, err)
+}
@@ -1,25 +1,34 @@
 package scaffolder
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
-	"text/template"
 
+	"github.com/BurntSushi/toml"
 	"github.com/felipevolpatto/genesis/internal/config"
+	"github.com/felipevolpatto/genesis/internal/glob"
+	"github.com/felipevolpatto/genesis/internal/pathutil"
+	"github.com/felipevolpatto/genesis/internal/render"
 )
 
+// skipIfEmptyMarker marks a template file, usually inside a template
+// comment, to be skipped when it renders to nothing but whitespace
+const skipIfEmptyMarker = "genesis:skip-if-empty"
+
 // Scaffolder handles the project scaffolding process
 type Scaffolder struct {
 	templateDir string
 	targetDir   string
-	variables   map[string]string
+	variables   map[string]interface{}
 	config      *config.TemplateConfig
 }
 
 // New creates a new Scaffolder instance
-func New(templateDir, targetDir string, variables map[string]string, config *config.TemplateConfig) *Scaffolder {
+func New(templateDir, targetDir string, variables map[string]interface{}, config *config.TemplateConfig) *Scaffolder {
 	return &Scaffolder{
 		templateDir: templateDir,
 		targetDir:   targetDir,
@@ -35,8 +44,11 @@ func (s *Scaffolder) Scaffold() error {
 		return fmt.Errorf("failed to create target directory: %w", err)
 	}
 
+	// Directories of files that were skipped because they rendered empty
+	var skippedDirs []string
+
 	// Walk through the template directory
-	return filepath.Walk(s.templateDir, func(path string, info os.FileInfo, err error) error {
+	err := filepath.Walk(s.templateDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -57,7 +69,25 @@ func (s *Scaffolder) Scaffold() error {
 			return fmt.Errorf("failed to get relative path: %w", err)
 		}
 
-		// Create target path
+		// Skip files and directories excluded by the [files] section
+		if relPath != "." {
+			include, err := s.included(relPath)
+			if err != nil {
+				return err
+			}
+			if !include {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+
+		// Render templated path segments and create target path
+		relPath, err = s.renderPath(relPath)
+		if err != nil {
+			return err
+		}
 		targetPath := filepath.Join(s.targetDir, relPath)
 
 		if info.IsDir() {
@@ -66,38 +96,159 @@ func (s *Scaffolder) Scaffold() error {
 
 		// Process or copy the file
 		if strings.HasSuffix(path, ".tmpl") {
-			return s.processTemplate(path, strings.TrimSuffix(targetPath, ".tmpl"))
+			written, err := s.processTemplate(path, strings.TrimSuffix(targetPath, ".tmpl"))
+			if err == nil && !written {
+				skippedDirs = append(skippedDirs, filepath.Dir(targetPath))
+			}
+			return err
 		}
 
 		return s.copyFile(path, targetPath)
 	})
+	if err != nil {
+		return err
+	}
+
+	return s.pruneEmptyDirs(skippedDirs)
 }
 
-// processTemplate processes a template file and writes the result
-func (s *Scaffolder) processTemplate(src, dst string) error {
+// processTemplate processes a template file and writes the result. Output
+// that is empty or only whitespace is not written if the template opts in
+// to skipping empty files, either for all files or with the skip-if-empty
+// marker. It reports whether the file was written.
+func (s *Scaffolder) processTemplate(src, dst string) (bool, error) {
 	content, err := os.ReadFile(src)
 	if err != nil {
-		return fmt.Errorf("failed to read template file: %w", err)
+		return false, fmt.Errorf("failed to read template file: %w", err)
 	}
 
-	tmpl, err := template.New(filepath.Base(src)).Parse(string(content))
+	out, err := s.renderString(filepath.Base(src), string(content))
 	if err != nil {
-		return fmt.Errorf("failed to parse template: %w", err)
+		return false, err
 	}
 
-	out, err := os.Create(dst)
-	if err != nil {
-		return fmt.Errorf("failed to create output file: %w", err)
+	skipEmpty := (s.config != nil && s.config.Files.SkipEmpty) || bytes.Contains(content, []byte(skipIfEmptyMarker))
+	if skipEmpty && strings.TrimSpace(out) == "" {
+		return false, nil
 	}
-	defer out.Close()
 
-	if err := tmpl.Execute(out, s.variables); err != nil {
-		return fmt.Errorf("failed to execute template: %w", err)
+	if err := os.WriteFile(dst, []byte(out), 0644); err != nil {
+		return false, fmt.Errorf("failed to create output file: %w", err)
 	}
 
+	return true, nil
+}
+
+// pruneEmptyDirs removes each of the given directories, and then its
+// parents, for as long as they are empty. The target directory itself is
+// never removed.
+func (s *Scaffolder) pruneEmptyDirs(dirs []string) error {
+	root := filepath.Clean(s.targetDir)
+	for _, dir := range dirs {
+		for dir = filepath.Clean(dir); pathutil.Within(dir, root); dir = filepath.Dir(dir) {
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				return fmt.Errorf("failed to read directory: %w", err)
+			}
+			if len(entries) > 0 {
+				break
+			}
+			if err := os.Remove(dir); err != nil {
+				return fmt.Errorf("failed to remove empty directory: %w", err)
+			}
+		}
+	}
 	return nil
 }
 
+// included reports whether a template file or directory, given by its path
+// relative to the template root, is part of the generated project. It is
+// left out when it matches an exclude pattern or a rule whose condition is
+// false.
+func (s *Scaffolder) included(relPath string) (bool, error) {
+	if s.config == nil {
+		return true, nil
+	}
+
+	name := filepath.ToSlash(relPath)
+	for _, pattern := range s.config.Files.Exclude {
+		if glob.Match(pattern, name) {
+			return false, nil
+		}
+	}
+
+	for _, rule := range s.config.Files.Rules {
+		if !glob.Match(rule.Path, name) {
+			continue
+		}
+		ok, err := render.Condition("when", rule.When, s.variables)
+		if err != nil {
+			return false, fmt.Errorf("failed to evaluate condition for %s: %w", rule.Path, err)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// RenderHooks renders each hook command as a template with the same
+// variables used for template files. Referring to an undefined variable is
+// an error, so that no half-rendered command reaches the shell.
+func (s *Scaffolder) RenderHooks(hooks []string) ([]string, error) {
+	rendered := make([]string, 0, len(hooks))
+	for _, hook := range hooks {
+		cmd, err := render.Expr("hook", hook, s.variables)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render hook %q: %w", hook, err)
+		}
+		rendered = append(rendered, cmd)
+	}
+	return rendered, nil
+}
+
+// renderString renders text as a template with the scaffolder's variables
+func (s *Scaffolder) renderString(name, text string) (string, error) {
+	return render.String(name, text, s.variables)
+}
+
+// renderPath renders every segment of a relative path that contains a
+// template action. A segment must render to a single, non-empty name and
+// may not refer to an undefined variable.
+func (s *Scaffolder) renderPath(relPath string) (string, error) {
+	if !strings.Contains(relPath, "{{") {
+		return relPath, nil
+	}
+
+	segments := strings.Split(relPath, string(filepath.Separator))
+	for i, segment := range segments {
+		if !strings.Contains(segment, "{{") {
+			continue
+		}
+
+		name, err := render.Expr(segment, segment, s.variables)
+		if err != nil {
+			return "", fmt.Errorf("failed to render path %s: %w", relPath, err)
+		}
+
+		switch {
+		case strings.TrimSpace(name) == "":
+			return "", fmt.Errorf("path segment %q of %s renders to an empty name", segment, relPath)
+		case strings.ContainsAny(name, `/\`):
+			return "", fmt.Errorf("path segment %q of %s renders to %q, which contains a path separator", segment, relPath, name)
+		case name == "." || name == "..":
+			return "", fmt.Errorf("path segment %q of %s renders to %q", segment, relPath, name)
+		}
+		segments[i] = name
+	}
+
+	return filepath.Join(segments...), nil
+}
+
 // copyFile copies a file from src to dst
 func (s *Scaffolder) copyFile(src, dst string) error {
 	content, err := os.ReadFile(src)
@@ -108,18 +259,131 @@ func (s *Scaffolder) copyFile(src, dst string) error {
 	return os.WriteFile(dst, content, 0644)
 }
 
-// CreateGenesisConfig creates a genesis.toml file in the target directory
-func (s *Scaffolder) CreateGenesisConfig(templateURL, templateVersion string) error {
+// CreateGenesisConfig creates a genesis.toml file in the target directory.
+// Next to the template version as given, it records the commit it resolved
+// to, if known, so the project can be rendered again reproducibly. The
+// answers given for the template variables are recorded in the
+// [project.answers] table so the project can be generated again; secret and
+// computed variables are left out.
+func (s *Scaffolder) CreateGenesisConfig(templateURL, templateVersion, templateCommit string) error {
+	project, err := s.projectTable(templateURL, templateVersion, templateCommit)
+	if err != nil {
+		return err
+	}
+
 	config := fmt.Sprintf(`# The version of the genesis config spec
 version = "1.0"
 
-[project]
-  template_url = %q
-  template_version = %q
-
+%s
 # [tasks] defines the commands that can be run with 'genesis run <task-name>'
 [tasks]
-`, templateURL, templateVersion)
+`, project)
 
 	return os.WriteFile(filepath.Join(s.targetDir, "genesis.toml"), []byte(config), 0644)
-} 
\ No newline at end of file
+}
+
+// UpdateGenesisConfig replaces the [project] table of the genesis.toml file
+// in the target directory, including its answers, the same way
+// CreateGenesisConfig writes it. The rest of the file, such as the tasks,
+// is kept as is.
+func (s *Scaffolder) UpdateGenesisConfig(templateURL, templateVersion, templateCommit string) error {
+	path := filepath.Join(s.targetDir, "genesis.toml")
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read genesis.toml: %w", err)
+	}
+
+	// Find the lines of the [project] table and its subtables
+	lines := strings.SplitAfter(string(content), "\n")
+	start, end := -1, len(lines)
+	for i, line := range lines {
+		match := tableHeader.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		if match[1] == "project" || strings.HasPrefix(match[1], "project.") {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			end = i
+			break
+		}
+	}
+	if start < 0 {
+		return fmt.Errorf("genesis.toml has no [project] table")
+	}
+
+	// Comments and blank lines before the next table belong to it
+	for end > start+1 {
+		line := strings.TrimSpace(lines[end-1])
+		if line != "" && !strings.HasPrefix(line, "#") {
+			break
+		}
+		end--
+	}
+
+	project, err := s.projectTable(templateURL, templateVersion, templateCommit)
+	if err != nil {
+		return err
+	}
+
+	updated := strings.Join(lines[:start], "") + project + strings.Join(lines[end:], "")
+	return os.WriteFile(path, []byte(updated), 0644)
+}
+
+// tableHeader matches a TOML table header and captures the table name
+var tableHeader = regexp.MustCompile(`^\s*\[\[?\s*([A-Za-z0-9_.-]+)\s*\]\]?\s*(#.*)?$`)
+
+// projectTable returns the [project] table of genesis.toml, followed by the
+// [project.answers] table if there are answers to record
+func (s *Scaffolder) projectTable(templateURL, templateVersion, templateCommit string) (string, error) {
+	answers, err := s.recordedAnswers()
+	if err != nil {
+		return "", err
+	}
+
+	commit := ""
+	if templateCommit != "" {
+		commit = fmt.Sprintf("  template_commit = %q\n", templateCommit)
+	}
+
+	return fmt.Sprintf(`[project]
+  template_url = %q
+  template_version = %q
+%s%s`, templateURL, templateVersion, commit, answers), nil
+}
+
+// recordedAnswers encodes the variables to record in genesis.toml as a
+// [project.answers] table, or returns an empty string if there are none
+func (s *Scaffolder) recordedAnswers() (string, error) {
+	if s.config == nil {
+		return "", nil
+	}
+
+	answers := make(map[string]interface{})
+	for name, v := range s.config.Vars {
+		value, ok := s.variables[name]
+		if !ok || v.Secret || v.Value != "" {
+			continue
+		}
+		answers[name] = value
+	}
+	if len(answers) == 0 {
+		return "", nil
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(answers); err != nil {
+		return "", fmt.Errorf("failed to encode answers: %w", err)
+	}
+
+	var table strings.Builder
+	table.WriteString("\n[project.answers]\n")
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		table.WriteString("  " + line + "\n")
+	}
+	return table.String(), nil
+}
@@ -44,7 +44,7 @@ func main() {
 	}
 
 	// Create variables
-	variables := map[string]string{
+	variables := map[string]interface{}{
 		"name":        "Test",
 		"description": "A test description",
 	}
@@ -84,8 +84,9 @@ func TestCreateGenesisConfig(t *testing.T) {
 
 	templateURL := "https://github.com/example/template"
 	templateVersion := "v1.0.0"
+	templateCommit := "4eeee43170429c82eb2fc4eeef52fbd2d8b2d0ca"
 
-	err := s.CreateGenesisConfig(templateURL, templateVersion)
+	err := s.CreateGenesisConfig(templateURL, templateVersion, templateCommit)
 	require.NoError(t, err)
 
 	// Verify genesis.toml was created correctly
@@ -98,6 +99,7 @@ version = "1.0"
 [project]
   template_url = "https://github.com/example/template"
   template_version = "v1.0.0"
+  template_commit = "4eeee43170429c82eb2fc4eeef52fbd2d8b2d0ca"
 
 # [tasks] defines the commands that can be run with 'genesis run <task-name>'
 [tasks]
@@ -106,6 +108,121 @@ version = "1.0"
 	assert.Equal(t, expectedContent, string(content))
 }
 
+func TestCreateGenesisConfigAnswers(t *testing.T) {
+	targetDir := t.TempDir()
+	templateConfig := &config.TemplateConfig{
+		Version: "1.0",
+		Vars: map[string]config.Variable{
+			"name":     {Prompt: "Name:"},
+			"port":     {Prompt: "Port:", Type: config.TypeInt},
+			"features": {Prompt: "Features:", Type: config.TypeMultiSelect, Choices: []string{"ci", "docs"}},
+			"token":    {Prompt: "Token:", Secret: true},
+			"slug":     {Value: "{{ .name }}"},
+		},
+	}
+	variables := map[string]interface{}{
+		"name":     "shop",
+		"port":     8080,
+		"features": []string{"ci", "docs"},
+		"token":    "s3cret",
+		"slug":     "shop",
+	}
+	s := New("", targetDir, variables, templateConfig)
+
+	err := s.CreateGenesisConfig("https://github.com/example/template", "v1.0.0", "")
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(targetDir, "genesis.toml"))
+	require.NoError(t, err)
+
+	// Secret and computed variables are not recorded
+	expectedContent := `# The version of the genesis config spec
+version = "1.0"
+
+[project]
+  template_url = "https://github.com/example/template"
+  template_version = "v1.0.0"
+
+[project.answers]
+  features = ["ci", "docs"]
+  name = "shop"
+  port = 8080
+
+# [tasks] defines the commands that can be run with 'genesis run <task-name>'
+[tasks]
+`
+	assert.Equal(t, expectedContent, string(content))
+
+	projectConfig, err := config.ParseProjectConfig(filepath.Join(targetDir, "genesis.toml"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"features": []interface{}{"ci", "docs"},
+		"name":     "shop",
+		"port":     int64(8080),
+	}, projectConfig.Project.Answers)
+}
+
+func TestUpdateGenesisConfig(t *testing.T) {
+	targetDir := t.TempDir()
+	existing := `# The version of the genesis config spec
+version = "1.0"
+
+[project]
+  template_url = "https://github.com/example/template"
+  template_version = "v1.0.0"
+  template_commit = "4eeee43170429c82eb2fc4eeef52fbd2d8b2d0ca"
+
+[project.answers]
+  name = "shop"
+  removed = "old"
+
+# [tasks] defines the commands that can be run with 'genesis run <task-name>'
+[tasks]
+  test = { cmd = "go test ./..." }
+`
+	err := os.WriteFile(filepath.Join(targetDir, "genesis.toml"), []byte(existing), 0644)
+	require.NoError(t, err)
+
+	templateConfig := &config.TemplateConfig{
+		Version: "1.0",
+		Vars: map[string]config.Variable{
+			"name": {Prompt: "Name:"},
+			"port": {Prompt: "Port:", Type: config.TypeInt},
+		},
+	}
+	s := New("", targetDir, map[string]interface{}{"name": "shop", "port": 8080}, templateConfig)
+
+	err = s.UpdateGenesisConfig("https://github.com/example/template", "v2.0.0", "c0ffee0c0ffee0c0ffee0c0ffee0c0ffee0c0ffe")
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(targetDir, "genesis.toml"))
+	require.NoError(t, err)
+
+	// Only the project table changes
+	expectedContent := `# The version of the genesis config spec
+version = "1.0"
+
+[project]
+  template_url = "https://github.com/example/template"
+  template_version = "v2.0.0"
+  template_commit = "c0ffee0c0ffee0c0ffee0c0ffee0c0ffee0c0ffe"
+
+[project.answers]
+  name = "shop"
+  port = 8080
+
+# [tasks] defines the commands that can be run with 'genesis run <task-name>'
+[tasks]
+  test = { cmd = "go test ./..." }
+`
+	assert.Equal(t, expectedContent, string(content))
+
+	err = os.WriteFile(filepath.Join(targetDir, "genesis.toml"), []byte("version = \"1.0\"\n"), 0644)
+	require.NoError(t, err)
+	err = s.UpdateGenesisConfig("https://github.com/example/template", "v2.0.0", "")
+	assert.ErrorContains(t, err, "no [project] table")
+}
+
 func TestScaffolderErrors(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -159,4 +276,350 @@ func TestScaffolderErrors(t *testing.T) {
 			}
 		})
 	}
-} 
\ No newline at end of file
+}
+
+func TestRenderHooks(t *testing.T) {
+	variables := map[string]interface{}{"name": "demo"}
+	s := New(t.TempDir(), t.TempDir(), variables, &config.TemplateConfig{Version: "1.0"})
+
+	tests := []struct {
+		name          string
+		hooks         []string
+		expected      []string
+		expectedError string
+	}{
+		{
+			name:     "no hooks",
+			expected: []string{},
+		},
+		{
+			name:     "plain and templated hooks",
+			hooks:    []string{"git init", "git commit -m 'Init {{.name}}'"},
+			expected: []string{"git init", "git commit -m 'Init demo'"},
+		},
+		{
+			name:          "invalid template",
+			hooks:         []string{"echo {{ .name"},
+			expectedError: `failed to render hook "echo {{ .name"`,
+		},
+		{
+			name:          "undefined variable",
+			hooks:         []string{"echo {{ .nmae }} > hook.txt"},
+			expectedError: `map has no entry for key "nmae"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hooks, err := s.RenderHooks(tt.hooks)
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, hooks)
+		})
+	}
+}
+
+func TestScaffolderTemplatedPaths(t *testing.T) {
+	templateDir := t.TempDir()
+	targetDir := t.TempDir()
+
+	files := map[string]string{
+		"cmd/{{.name}}/main.go.tmpl":                                         "package main // {{ .name }}",
+		"{{.package}}/models.py":                                             "# models",
+		"{{.package}}/{{.name}}_test.py":                                     "# tests",
+		"docs/{{ if eq .license \"mit\" }}LICENSE{{ else }}COPYING{{ end }}": "license",
+	}
+
+	for name, content := range files {
+		path := filepath.Join(templateDir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	variables := map[string]interface{}{
+		"name":    "demo",
+		"package": "shop",
+		"license": "mit",
+	}
+	s := New(templateDir, targetDir, variables, &config.TemplateConfig{Version: "1.0"})
+	require.NoError(t, s.Scaffold())
+
+	expected := map[string]string{
+		"cmd/demo/main.go":  "package main // demo",
+		"shop/models.py":    "# models",
+		"shop/demo_test.py": "# tests",
+		"docs/LICENSE":      "license",
+	}
+	for name, expectedContent := range expected {
+		content, err := os.ReadFile(filepath.Join(targetDir, filepath.FromSlash(name)))
+		require.NoError(t, err)
+		assert.Equal(t, expectedContent, string(content))
+	}
+
+	// Unrendered names are not created
+	_, err := os.Stat(filepath.Join(targetDir, "{{.package}}"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestScaffolderTemplatedPathErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          string
+		variables     map[string]interface{}
+		expectedError string
+	}{
+		{
+			name:          "empty segment",
+			path:          "{{.package}}/models.py",
+			variables:     map[string]interface{}{"package": ""},
+			expectedError: "renders to an empty name",
+		},
+		{
+			name:          "separator in segment",
+			path:          "{{.package}}/models.py",
+			variables:     map[string]interface{}{"package": "com/example"},
+			expectedError: "contains a path separator",
+		},
+		{
+			name:          "parent directory",
+			path:          "{{.package}}/models.py",
+			variables:     map[string]interface{}{"package": ".."},
+			expectedError: `renders to ".."`,
+		},
+		{
+			name:          "undefined variable",
+			path:          "{{.pacakge}}/models.py",
+			variables:     map[string]interface{}{"package": "shop"},
+			expectedError: `map has no entry for key "pacakge"`,
+		},
+		{
+			name:          "invalid syntax",
+			path:          "{{.package/models.py",
+			variables:     map[string]interface{}{"package": "shop"},
+			expectedError: "failed to render path",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templateDir := t.TempDir()
+			path := filepath.Join(templateDir, filepath.FromSlash(tt.path))
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+			require.NoError(t, os.WriteFile(path, []byte(""), 0644))
+
+			s := New(templateDir, t.TempDir(), tt.variables, &config.TemplateConfig{Version: "1.0"})
+			err := s.Scaffold()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedError)
+		})
+	}
+}
+
+func TestScaffolderFileRules(t *testing.T) {
+	files := map[string]string{
+		"main.go":                 "package main",
+		"README.md":               "# readme",
+		"docs/notes.md":           "notes",
+		"docker/Dockerfile":       "FROM alpine",
+		"docker/compose.yml.tmpl": "name: {{ .name }}",
+		"postgres.yml":            "postgres",
+		"mysql.yml":               "mysql",
+	}
+
+	templateConfig := &config.TemplateConfig{
+		Version: "1.0",
+		Files: config.Files{
+			Exclude: []string{"docs/**"},
+			Rules: []config.FileRule{
+				{Path: "docker/**", When: `{{ eq .docker "yes" }}`},
+				{Path: "postgres.yml", When: `{{ eq .database "postgres" }}`},
+				{Path: "mysql.yml", When: `{{ eq .database "mysql" }}`},
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		variables map[string]interface{}
+		expected  []string
+	}{
+		{
+			name:      "all conditions hold",
+			variables: map[string]interface{}{"name": "demo", "docker": "yes", "database": "postgres"},
+			expected:  []string{"README.md", "docker/Dockerfile", "docker/compose.yml", "main.go", "postgres.yml"},
+		},
+		{
+			name:      "conditions do not hold",
+			variables: map[string]interface{}{"name": "demo", "docker": "no", "database": "mysql"},
+			expected:  []string{"README.md", "main.go", "mysql.yml"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templateDir := t.TempDir()
+			targetDir := t.TempDir()
+
+			for name, content := range files {
+				path := filepath.Join(templateDir, filepath.FromSlash(name))
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+				require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+			}
+
+			s := New(templateDir, targetDir, tt.variables, templateConfig)
+			require.NoError(t, s.Scaffold())
+
+			var created []string
+			err := filepath.Walk(targetDir, func(path string, info os.FileInfo, err error) error {
+				if err != nil {
+					return err
+				}
+				if info.IsDir() {
+					// Excluded directories are not created at all
+					assert.NotEqual(t, "docs", info.Name())
+					return nil
+				}
+				relPath, err := filepath.Rel(targetDir, path)
+				require.NoError(t, err)
+				created = append(created, filepath.ToSlash(relPath))
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, created)
+		})
+	}
+}
+
+func TestScaffolderFileRuleError(t *testing.T) {
+	templateDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(templateDir, "main.go"), []byte("package main"), 0644))
+
+	templateConfig := &config.TemplateConfig{
+		Version: "1.0",
+		Files: config.Files{
+			Rules: []config.FileRule{{Path: "*.go", When: "{{ .docker"}},
+		},
+	}
+
+	err := New(templateDir, t.TempDir(), nil, templateConfig).Scaffold()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to evaluate condition for *.go")
+}
+
+func TestScaffolderSkipEmpty(t *testing.T) {
+	files := map[string]string{
+		"main.go.tmpl":                      "package main",
+		"blank.txt.tmpl":                    "{{ if .docker }}content{{ end }}\n",
+		"docker/Dockerfile.tmpl":            "{{ if .docker }}FROM alpine{{ end }}",
+		"deploy/k8s/deployment.yml.tmpl":    "{{/* genesis:skip-if-empty */}}{{ if .docker }}kind: Deployment{{ end }}",
+		"deploy/README.md":                  "# deploy",
+		"optional/nested/settings.ini.tmpl": "  {{- /* genesis:skip-if-empty */ -}}  \n",
+	}
+
+	tests := []struct {
+		name      string
+		skipEmpty bool
+		expected  []string
+	}{
+		{
+			name:      "marked files only",
+			skipEmpty: false,
+			expected:  []string{"blank.txt", "deploy/README.md", "docker/Dockerfile", "main.go"},
+		},
+		{
+			name:      "all files",
+			skipEmpty: true,
+			expected:  []string{"deploy/README.md", "main.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templateDir := t.TempDir()
+			targetDir := t.TempDir()
+
+			for name, content := range files {
+				path := filepath.Join(templateDir, filepath.FromSlash(name))
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+				require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+			}
+
+			templateConfig := &config.TemplateConfig{
+				Version: "1.0",
+				Files:   config.Files{SkipEmpty: tt.skipEmpty},
+			}
+			s := New(templateDir, targetDir, map[string]interface{}{"docker": ""}, templateConfig)
+			require.NoError(t, s.Scaffold())
+
+			var created []string
+			err := filepath.Walk(targetDir, func(path string, info os.FileInfo, err error) error {
+				if err != nil {
+					return err
+				}
+				relPath, err := filepath.Rel(targetDir, path)
+				require.NoError(t, err)
+				if info.IsDir() {
+					// Directories left empty by skipped files are pruned
+					if relPath != "." {
+						entries, err := os.ReadDir(path)
+						require.NoError(t, err)
+						assert.NotEmpty(t, entries, relPath)
+					}
+					return nil
+				}
+				created = append(created, filepath.ToSlash(relPath))
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, created)
+		})
+	}
+}
+
+func TestPruneEmptyDirs(t *testing.T) {
+	base := t.TempDir()
+	targetDir := filepath.Join(base, "shop")
+	nested := filepath.Join(targetDir, "deploy", "k8s")
+	sibling := filepath.Join(base, "shop-old", "empty")
+	require.NoError(t, os.MkdirAll(nested, 0755))
+	require.NoError(t, os.MkdirAll(sibling, 0755))
+
+	s := New(t.TempDir(), targetDir, nil, nil)
+	require.NoError(t, s.pruneEmptyDirs([]string{nested, sibling}))
+
+	// Empty directories are removed up to, but not including, the target
+	_, err := os.Stat(filepath.Join(targetDir, "deploy"))
+	assert.True(t, os.IsNotExist(err))
+	assert.DirExists(t, targetDir)
+
+	// Directories outside the target are left alone, even when their name
+	// starts with the target's
+	assert.DirExists(t, sibling)
+}
+
+func TestScaffolderTypedVariables(t *testing.T) {
+	templateDir := t.TempDir()
+	targetDir := t.TempDir()
+
+	content := `{{ if .use_docker }}docker on port {{ .port }}{{ end }}
+{{ range .features }}- {{ . }}
+{{ end }}`
+	require.NoError(t, os.WriteFile(filepath.Join(templateDir, "README.md.tmpl"), []byte(content), 0644))
+
+	variables := map[string]interface{}{
+		"use_docker": true,
+		"port":       8080,
+		"features":   []string{"ci", "docs"},
+	}
+	s := New(templateDir, targetDir, variables, &config.TemplateConfig{Version: "1.0"})
+	require.NoError(t, s.Scaffold())
+
+	output, err := os.ReadFile(filepath.Join(targetDir, "README.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "docker on port 8080\n- ci\n- docs\n", string(output))
+}
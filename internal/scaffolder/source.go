@@ -0,0 +1,139 @@
+package scaffolder
+
+import (
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FetchTemplate makes the template at source available in a temporary
+// directory and returns the directory together with the commit it was
+// checked out at. Local directories, given as a path or a file:// URL, are
+// copied as they are, including uncommitted changes, unless a version is
+// requested; their commit is empty. All other sources, and local
+// directories when a version is requested, are cloned with CloneTemplate.
+// The directory is removed with CleanupTemplate.
+func FetchTemplate(source, version string) (string, string, error) {
+	path, local := localTemplatePath(source)
+	if local && version == "" {
+		dir, err := copyTemplate(path)
+		return dir, "", err
+	}
+	if local {
+		source = path
+	}
+
+	dir, err := CloneTemplate(source, version)
+	if err != nil {
+		return "", "", err
+	}
+	commit, err := TemplateCommit(dir)
+	if err != nil {
+		CleanupTemplate(dir)
+		return "", "", err
+	}
+	return dir, commit, nil
+}
+
+// ResolveSource returns a template source in the form it is recorded in
+// genesis.toml. Local directories are made absolute, so that the project
+// can be updated and compared from any working directory; other sources
+// are returned unchanged.
+func ResolveSource(source string) (string, error) {
+	path, local := localTemplatePath(source)
+	if !local {
+		return source, nil
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve template path: %w", err)
+	}
+	if strings.HasPrefix(source, "file://") {
+		return "file://" + filepath.ToSlash(abs), nil
+	}
+	return abs, nil
+}
+
+// IsLocal reports whether source refers to a local template directory
+func IsLocal(source string) bool {
+	_, local := localTemplatePath(source)
+	return local
+}
+
+// localTemplatePath returns the directory a template source refers to and
+// reports whether it is a local directory
+func localTemplatePath(source string) (string, bool) {
+	path := source
+	if strings.HasPrefix(source, "file://") {
+		u, err := url.Parse(source)
+		if err != nil {
+			return "", false
+		}
+		path = filepath.FromSlash(u.Path)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		return "", false
+	}
+	return path, true
+}
+
+// copyTemplate copies a local template directory, except for its .git
+// directory, to a temporary directory
+func copyTemplate(src string) (string, error) {
+	if _, err := os.Stat(filepath.Join(src, "template.toml")); err != nil {
+		return "", fmt.Errorf("invalid template: template.toml not found")
+	}
+
+	tempDir, err := os.MkdirTemp("", "genesis-template-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp directory: %w", err)
+	}
+
+	err = filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		dst := filepath.Join(tempDir, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(dst, info.Mode().Perm()|0700)
+		case d.Type()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(target, dst)
+		case d.Type().IsRegular():
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(dst, content, info.Mode().Perm())
+		}
+		return nil
+	})
+	if err != nil {
+		os.RemoveAll(tempDir)
+		return "", fmt.Errorf("failed to copy template: %w", err)
+	}
+
+	return tempDir, nil
+}
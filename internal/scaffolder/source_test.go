@@ -0,0 +1,89 @@
+package scaffolder
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchTemplateLocalDirectory(t *testing.T) {
+	src := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(src, "template.toml"), []byte(`version = "1.0"`), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "scripts"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "scripts", "setup.sh"), []byte("#!/bin/sh\n"), 0755))
+
+	for _, source := range []string{src, "file://" + filepath.ToSlash(src)} {
+		t.Run(source, func(t *testing.T) {
+			dir, commit, err := FetchTemplate(source, "")
+			require.NoError(t, err)
+			defer CleanupTemplate(dir)
+
+			assert.NotEqual(t, src, dir)
+			assert.Empty(t, commit)
+
+			info, err := os.Stat(filepath.Join(dir, "scripts", "setup.sh"))
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+		})
+	}
+
+	_, _, err := FetchTemplate(t.TempDir(), "")
+	assert.ErrorContains(t, err, "template.toml not found")
+}
+
+func TestFetchTemplateWorkingCopy(t *testing.T) {
+	repoDir, commit := setupTestRepo(t)
+
+	// Uncommitted changes are part of the template unless a version is
+	// requested
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "draft.txt"), []byte("draft\n"), 0644))
+
+	dir, fetchedCommit, err := FetchTemplate(repoDir, "")
+	require.NoError(t, err)
+	defer CleanupTemplate(dir)
+	assert.Empty(t, fetchedCommit)
+	assert.FileExists(t, filepath.Join(dir, "draft.txt"))
+	assert.NoDirExists(t, filepath.Join(dir, ".git"))
+
+	dir, fetchedCommit, err = FetchTemplate("file://"+filepath.ToSlash(repoDir), "v1.0.0")
+	require.NoError(t, err)
+	defer CleanupTemplate(dir)
+	assert.Equal(t, commit.String(), fetchedCommit)
+	assert.NoFileExists(t, filepath.Join(dir, "draft.txt"))
+}
+
+func TestResolveSource(t *testing.T) {
+	base := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(base, "templates", "go"), 0755))
+	abs := filepath.Join(base, "templates", "go")
+
+	currentDir, err := os.Getwd()
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.Chdir(currentDir))
+	}()
+	require.NoError(t, os.Chdir(base))
+
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{"templates/go", abs},
+		{"./templates/../templates/go", abs},
+		{abs, abs},
+		{"file://" + filepath.ToSlash(abs), "file://" + filepath.ToSlash(abs)},
+		{"https://github.com/example/template.git", "https://github.com/example/template.git"},
+		{"templates/missing", "templates/missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			source, err := ResolveSource(tt.source)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, source)
+		})
+	}
+}
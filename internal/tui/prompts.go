@@ -2,9 +2,11 @@ package tui
 
 import (
 	"errors"
+	"fmt"
 	"regexp"
 
 	"github.com/AlecAivazis/survey/v2"
+	"github.com/felipevolpatto/genesis/internal/answers"
 	"github.com/felipevolpatto/genesis/internal/config"
 )
 
@@ -13,43 +15,80 @@ var ErrInvalidRegexp = errors.New("invalid regular expression")
 // askOne is a variable that holds the survey.AskOne function
 var askOne = survey.AskOne
 
-// PromptForVariables asks the user for values for each template variable
-func PromptForVariables(vars map[string]config.Variable) (map[string]string, error) {
-	answers := make(map[string]string)
+// PromptForVariables asks the user for values for the named template
+// variables, in the order given. Each variable is asked with a prompt
+// matching its type, and the answers have the variable's Go type (see
+// config.Variable.Convert). Variables whose when condition does not hold
+// are not asked and take their default value.
+func PromptForVariables(vars map[string]config.Variable, names []string) (map[string]interface{}, error) {
+	return answers.Resolve(vars, names, Prompt)
+}
 
-	for name, v := range vars {
-		prompt := &survey.Input{
-			Message: v.Prompt,
-			Default: v.Default,
-		}
+// Prompt is an answers.AskFunc that asks the user for the value of a
+// variable with a prompt matching its type
+func Prompt(name string, v config.Variable) (interface{}, error) {
+	return promptForVariable(v)
+}
 
-		var answer string
-		var err error
-
-		if v.Regex != "" {
-			regex := regexp.MustCompile(v.Regex)
-			err = askOne(prompt, &answer, survey.WithValidator(func(val interface{}) error {
-				str, ok := val.(string)
-				if !ok {
-					return nil
-				}
-				if !regex.MatchString(str) {
-					return ErrInvalidRegexp
-				}
-				return nil
-			}))
-		} else {
-			err = askOne(prompt, &answer)
-		}
+// promptForVariable asks for the value of a single variable
+func promptForVariable(v config.Variable) (interface{}, error) {
+	switch v.Kind() {
+	case config.TypeBool:
+		def, _ := v.Default.(bool)
+		var answer bool
+		err := askOne(&survey.Confirm{Message: v.Prompt, Default: def}, &answer)
+		return answer, err
 
-		if err != nil {
+	case config.TypeInt:
+		var answer string
+		prompt := &survey.Input{Message: v.Prompt, Default: fmt.Sprint(v.Default)}
+		if err := askOne(prompt, &answer, survey.WithValidator(func(val interface{}) error {
+			_, err := v.Convert(val)
+			return err
+		})); err != nil {
 			return nil, err
 		}
+		return v.Convert(answer)
 
-		answers[name] = answer
+	case config.TypeChoice:
+		var answer string
+		prompt := &survey.Select{Message: v.Prompt, Options: v.Choices, Default: v.Default}
+		err := askOne(prompt, &answer)
+		return answer, err
+
+	case config.TypeMultiSelect:
+		answer := []string{}
+		prompt := &survey.MultiSelect{Message: v.Prompt, Options: v.Choices, Default: v.Default}
+		err := askOne(prompt, &answer)
+		return answer, err
+	}
+
+	def, _ := v.Default.(string)
+	prompt := &survey.Input{
+		Message: v.Prompt,
+		Default: def,
+	}
+
+	var answer string
+	var err error
+
+	if v.Regex != "" {
+		regex := regexp.MustCompile(v.Regex)
+		err = askOne(prompt, &answer, survey.WithValidator(func(val interface{}) error {
+			str, ok := val.(string)
+			if !ok {
+				return nil
+			}
+			if !regex.MatchString(str) {
+				return ErrInvalidRegexp
+			}
+			return nil
+		}))
+	} else {
+		err = askOne(prompt, &answer)
 	}
 
-	return answers, nil
+	return answer, err
 }
 
 // ConfirmAction asks the user to confirm an action
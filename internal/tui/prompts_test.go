@@ -6,6 +6,7 @@ import (
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/felipevolpatto/genesis/internal/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPromptForVariables(t *testing.T) {
@@ -68,7 +69,8 @@ func TestPromptForVariables(t *testing.T) {
 			}
 			defer func() { askOne = oldAskOne }()
 
-			answers, err := PromptForVariables(tt.vars)
+			names := (&config.TemplateConfig{Vars: tt.vars}).VarNames()
+			answers, err := PromptForVariables(tt.vars, names)
 			if tt.expectError {
 				assert.Error(t, err)
 				return
@@ -85,6 +87,96 @@ func TestPromptForVariables(t *testing.T) {
 	}
 }
 
+func TestPromptForTypedVariables(t *testing.T) {
+	vars := map[string]config.Variable{
+		"use_docker": {Prompt: "Use Docker?", Type: config.TypeBool, Default: true},
+		"port":       {Prompt: "Port:", Type: config.TypeInt, Default: 8080},
+		"license":    {Prompt: "License:", Type: config.TypeChoice, Choices: []string{"MIT", "Apache-2.0"}, Default: "MIT"},
+		"features":   {Prompt: "Features:", Type: config.TypeMultiSelect, Choices: []string{"ci", "docs", "lint"}, Default: []string{"ci"}},
+	}
+
+	// Set up mock answering each prompt according to its kind
+	oldAskOne := askOne
+	askOne = func(p survey.Prompt, response interface{}, opts ...survey.AskOpt) error {
+		switch prompt := p.(type) {
+		case *survey.Confirm:
+			assert.Equal(t, true, prompt.Default)
+			*response.(*bool) = false
+		case *survey.Input:
+			assert.Equal(t, "8080", prompt.Default)
+			*response.(*string) = "9090"
+		case *survey.Select:
+			assert.Equal(t, []string{"MIT", "Apache-2.0"}, prompt.Options)
+			*response.(*string) = "Apache-2.0"
+		case *survey.MultiSelect:
+			assert.Equal(t, []string{"ci"}, prompt.Default)
+			*response.(*[]string) = []string{"docs", "lint"}
+		default:
+			t.Fatalf("unexpected prompt %T", p)
+		}
+		return nil
+	}
+	defer func() { askOne = oldAskOne }()
+
+	answers, err := PromptForVariables(vars, []string{"use_docker", "port", "license", "features"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"use_docker": false,
+		"port":       9090,
+		"license":    "Apache-2.0",
+		"features":   []string{"docs", "lint"},
+	}, answers)
+}
+
+func TestPromptForVariablesOrder(t *testing.T) {
+	vars := map[string]config.Variable{
+		"name":        {Prompt: "Name:"},
+		"module":      {Prompt: "Module:"},
+		"description": {Prompt: "Description:"},
+	}
+
+	// Set up mock recording the order of the prompts
+	var asked []string
+	oldAskOne := askOne
+	askOne = func(p survey.Prompt, response interface{}, opts ...survey.AskOpt) error {
+		asked = append(asked, p.(*survey.Input).Message)
+		return nil
+	}
+	defer func() { askOne = oldAskOne }()
+
+	for i := 0; i < 10; i++ {
+		asked = nil
+		_, err := PromptForVariables(vars, []string{"name", "module", "description"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Name:", "Module:", "Description:"}, asked)
+	}
+
+	_, err := PromptForVariables(vars, []string{"missing"})
+	assert.Error(t, err)
+}
+
+func TestPromptForVariablesWhen(t *testing.T) {
+	vars := map[string]config.Variable{
+		"database": {Prompt: "Database:", Type: config.TypeChoice, Choices: []string{"none", "postgres"}, Default: "none"},
+		"db_name":  {Prompt: "Database name:", Default: "app", When: `{{ ne .database "none" }}`},
+	}
+
+	// Set up mock choosing no database
+	var asked []string
+	oldAskOne := askOne
+	askOne = func(p survey.Prompt, response interface{}, opts ...survey.AskOpt) error {
+		asked = append(asked, p.(*survey.Select).Message)
+		*response.(*string) = "none"
+		return nil
+	}
+	defer func() { askOne = oldAskOne }()
+
+	answers, err := PromptForVariables(vars, []string{"database", "db_name"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Database:"}, asked)
+	assert.Equal(t, map[string]interface{}{"database": "none", "db_name": "app"}, answers)
+}
+
 func TestConfirmAction(t *testing.T) {
 	tests := []struct {
 		name        string
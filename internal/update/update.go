@@ -0,0 +1,206 @@
+// Package update applies the changes between two renderings of a template
+// to a project generated from it.
+package update
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/felipevolpatto/genesis/internal/diff"
+	"github.com/felipevolpatto/genesis/internal/pathutil"
+)
+
+// Result lists the files an update touched by their slash-separated path
+// relative to the project
+type Result struct {
+	// Updated files were replaced, as the project had not modified them
+	Updated []string
+	// Merged files combine the template changes with local modifications
+	Merged []string
+	// Added files are new in the template
+	Added []string
+	// Removed files were removed from the template
+	Removed []string
+	// Kept files were removed from the template but are left in place
+	// because the project modified them
+	Kept []string
+	// Conflicts are files whose local modifications conflict with the
+	// template changes. Text files contain conflict markers; for binary
+	// files, and files the project deleted but the template changed, the
+	// template version is written next to them with a .rej suffix.
+	Conflicts []string
+}
+
+// Apply applies the changes from the template rendered in oldDir to the
+// template rendered in newDir to the project in projectDir, as a
+// three-way merge with the old rendering as the common base. Conflict
+// markers name the project side "project" and the template side
+// templateLabel. The genesis.toml file at the root is not touched.
+func Apply(oldDir, newDir, projectDir, templateLabel string) (*Result, error) {
+	oldFiles, err := listFiles(oldDir)
+	if err != nil {
+		return nil, err
+	}
+	newFiles, err := listFiles(newDir)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(newFiles))
+	for path := range oldFiles {
+		paths = append(paths, path)
+	}
+	for path := range newFiles {
+		if !oldFiles[path] {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	result := &Result{}
+	for _, path := range paths {
+		if path == "genesis.toml" {
+			continue
+		}
+		if err := applyFile(result, path, oldDir, newDir, projectDir, templateLabel); err != nil {
+			return nil, fmt.Errorf("failed to update %s: %w", path, err)
+		}
+	}
+	return result, nil
+}
+
+// applyFile applies the template changes of a single file
+func applyFile(result *Result, path, oldDir, newDir, projectDir, templateLabel string) error {
+	base, inBase, err := readFile(oldDir, path)
+	if err != nil {
+		return err
+	}
+	theirs, inTheirs, err := readFile(newDir, path)
+	if err != nil {
+		return err
+	}
+	ours, inOurs, err := readFile(projectDir, path)
+	if err != nil {
+		return err
+	}
+	target := filepath.Join(projectDir, filepath.FromSlash(path))
+
+	switch {
+	case inBase && inTheirs && bytes.Equal(base, theirs):
+		// Unchanged in the template
+		return nil
+
+	case !inTheirs:
+		// Removed from the template
+		switch {
+		case !inOurs:
+			return nil
+		case bytes.Equal(ours, base):
+			result.Removed = append(result.Removed, path)
+			return removeFile(target, projectDir)
+		default:
+			result.Kept = append(result.Kept, path)
+			return nil
+		}
+
+	case !inOurs && !inBase:
+		// Added to the template
+		result.Added = append(result.Added, path)
+		return writeFile(target, theirs)
+
+	case !inOurs:
+		// Deleted from the project but changed in the template
+		result.Conflicts = append(result.Conflicts, path)
+		return writeFile(target+".rej", theirs)
+
+	case bytes.Equal(ours, theirs):
+		// The project already matches the template
+		return nil
+
+	case inBase && bytes.Equal(ours, base):
+		result.Updated = append(result.Updated, path)
+		return writeFile(target, theirs)
+	}
+
+	// Both the project and the template changed the file
+	if diff.IsBinary(base) || diff.IsBinary(ours) || diff.IsBinary(theirs) {
+		result.Conflicts = append(result.Conflicts, path)
+		return writeFile(target+".rej", theirs)
+	}
+
+	merged, conflict := diff.Merge(string(base), string(ours), string(theirs), "project", templateLabel)
+	if conflict {
+		result.Conflicts = append(result.Conflicts, path)
+	} else {
+		result.Merged = append(result.Merged, path)
+	}
+	return writeFile(target, []byte(merged))
+}
+
+// listFiles returns the slash-separated paths of the regular files below
+// dir, relative to it
+func listFiles(dir string) (map[string]bool, error) {
+	files := make(map[string]bool)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = true
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files of %s: %w", dir, err)
+	}
+	return files, nil
+}
+
+// readFile reads a file given by its slash-separated path relative to dir
+// and reports whether it exists
+func readFile(dir, path string) ([]byte, bool, error) {
+	content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(path)))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return content, true, nil
+}
+
+// writeFile writes a file, creating its directory if needed. Existing files
+// keep their permissions.
+func writeFile(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0644)
+}
+
+// removeFile removes a file and then its parent directories up to root for
+// as long as they are empty
+func removeFile(path, root string) error {
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	for dir := filepath.Dir(path); pathutil.Within(dir, root); dir = filepath.Dir(dir) {
+		entries, err := os.ReadDir(dir)
+		if err != nil || len(entries) > 0 {
+			break
+		}
+		if err := os.Remove(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package update
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFiles creates a directory containing the given files
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return dir
+}
+
+func TestApply(t *testing.T) {
+	oldDir := writeFiles(t, map[string]string{
+		"same.txt":         "same\n",
+		"updated.txt":      "v1\n",
+		"merged.txt":       "header\nv1\nbody\nfooter\n",
+		"conflict.txt":     "a\nv1\nc\n",
+		"removed/file.txt": "old\n",
+		"kept.txt":         "old\n",
+		"deleted.txt":      "v1\n",
+		"gone.txt":         "same\n",
+		"binary.bin":       "\x00v1",
+		"genesis.toml":     "v1\n",
+	})
+	newDir := writeFiles(t, map[string]string{
+		"same.txt":     "same\n",
+		"updated.txt":  "v2\n",
+		"merged.txt":   "header\nv2\nbody\nfooter\n",
+		"conflict.txt": "a\nv2\nc\n",
+		"deleted.txt":  "v2\n",
+		"gone.txt":     "same\n",
+		"binary.bin":   "\x00v2",
+		"added.txt":    "new\n",
+		"genesis.toml": "v2\n",
+	})
+	projectDir := writeFiles(t, map[string]string{
+		"same.txt":         "customized\n",
+		"updated.txt":      "v1\n",
+		"merged.txt":       "header\nv1\nbody\ncustom footer\n",
+		"conflict.txt":     "a\nmine\nc\n",
+		"removed/file.txt": "old\n",
+		"kept.txt":         "customized\n",
+		"binary.bin":       "\x00mine",
+		"genesis.toml":     "project\n",
+		"local.txt":        "local\n",
+	})
+
+	result, err := Apply(oldDir, newDir, projectDir, "template v2")
+	require.NoError(t, err)
+	assert.Equal(t, &Result{
+		Updated:   []string{"updated.txt"},
+		Merged:    []string{"merged.txt"},
+		Added:     []string{"added.txt"},
+		Removed:   []string{"removed/file.txt"},
+		Kept:      []string{"kept.txt"},
+		Conflicts: []string{"binary.bin", "conflict.txt", "deleted.txt"},
+	}, result)
+
+	expected := map[string]string{
+		"same.txt":        "customized\n",
+		"updated.txt":     "v2\n",
+		"merged.txt":      "header\nv2\nbody\ncustom footer\n",
+		"conflict.txt":    "a\n<<<<<<< project\nmine\n=======\nv2\n>>>>>>> template v2\nc\n",
+		"kept.txt":        "customized\n",
+		"binary.bin":      "\x00mine",
+		"binary.bin.rej":  "\x00v2",
+		"deleted.txt.rej": "v2\n",
+		"added.txt":       "new\n",
+		"genesis.toml":    "project\n",
+		"local.txt":       "local\n",
+	}
+	for name, content := range expected {
+		actual, err := os.ReadFile(filepath.Join(projectDir, name))
+		require.NoError(t, err, name)
+		assert.Equal(t, content, string(actual), name)
+	}
+
+	// Files removed from the template are removed along with their empty
+	// directory, and files deleted from the project stay deleted; when the
+	// template changed them, its version is left in a .rej file instead
+	_, err = os.Stat(filepath.Join(projectDir, "removed"))
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(projectDir, "deleted.txt"))
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(projectDir, "gone.txt"))
+	assert.True(t, os.IsNotExist(err))
+}